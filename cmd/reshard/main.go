@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/posts"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/reshard"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/app"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/db"
+)
+
+// reshard moves the posts from the previous layout of the posts shards topology to the current one, see the README for the whole procedure
+func main() {
+	step := flag.String("step", "plan", "one of: plan, prepare, copy, verify, cleanup")
+	batchSize := flag.Int("batch", 100, "number of posts read from a shard at once")
+	statePath := flag.String("state", "reshard.state.json", "path to the file with the progress")
+	full := flag.Bool("full", false, "copy all posts instead of the changes since the previous pass")
+	confirm := flag.Bool("confirm", false, "confirm the cleanup step, the moved posts are deleted from the source shards")
+	flag.Parse()
+
+	app.LoadEnv()
+
+	topology, err := services.LoadPostsTopology()
+	if err != nil {
+		log.Fatalf("unable to load posts shards topology: %s", err)
+	}
+	previousWeights := topology.PreviousWeights()
+	if previousWeights == nil {
+		log.Fatalf("the posts shards topology has no previous layout, nothing to move")
+	}
+
+	state, err := reshard.LoadState(*statePath, previousWeights, topology.Weights())
+	if err != nil {
+		log.Fatalf("unable to load state: %s", err)
+	}
+
+	shards := []*db.PostgreSQLService{}
+	for _, shardConfig := range topology.Shards {
+		client, err := posts.CreateShardClient(shardConfig)
+		if err != nil {
+			log.Fatalf("unable to create shard client: %s", err)
+		}
+		shards = append(shards, client)
+	}
+	defer func() {
+		for _, shard := range shards {
+			shard.Shutdown()
+		}
+	}()
+
+	resharder, err := reshard.CreateResharder(shards, state, *batchSize)
+	if err != nil {
+		log.Fatalf("unable to create resharder: %s", err)
+	}
+
+	err = run(resharder, *step, *full, *confirm)
+	if err != nil {
+		log.Error(fmt.Sprintf("Step '%v' failed", *step), err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(resharder *reshard.Resharder, step string, full bool, confirm bool) error {
+	switch step {
+	case "plan":
+		return resharder.Plan()
+	case "prepare":
+		return resharder.Prepare()
+	case "copy":
+		return resharder.Copy(full)
+	case "verify":
+		report, err := resharder.Verify()
+		if err != nil {
+			return err
+		}
+		if report.Failed() {
+			return fmt.Errorf("%v posts are missing and %v posts differ at the target shards", report.Missing, report.Mismatched)
+		}
+		return nil
+	case "cleanup":
+		if !confirm {
+			return fmt.Errorf("cleanup deletes the moved posts from the source shards, run it with -confirm after the dual-read window is closed")
+		}
+		return resharder.Cleanup()
+	default:
+		return fmt.Errorf("unknown step '%v'", step)
+	}
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+)
+
+// Cache entries are invalidated after the changes are committed. The failures are only logged, the entries expire by TTL anyway
+
+func invalidatePostsInCache(ctx context.Context, postUuids ...string) {
+	if len(postUuids) == 0 {
+		return
+	}
+	tags := make([]string, 0, len(postUuids))
+	keys := make([]string, 0, 2*len(postUuids))
+	for _, postUuid := range postUuids {
+		tags = append(tags, services.PostCacheTag(postUuid))
+		// the entries put into the cache before tagging was introduced are known only by keys
+		keys = append(keys, services.PostCacheKey(postUuid, false), services.PostCacheKey(postUuid, true))
+	}
+	invalidateCacheTags(ctx, tags...)
+	err := services.DeleteFromCache(ctx, keys...)
+	if err != nil {
+		log.Error(fmt.Sprintf("Unable to delete posts %v from the cache", postUuids), err.Error())
+	}
+}
+
+func invalidatePostsWithTagInCache(ctx context.Context, tagId int) {
+	invalidateCacheTags(ctx, services.PostsWithTagCacheTag(tagId))
+}
+
+func invalidateCommentsInCache(ctx context.Context, postUuid string) {
+	invalidateCacheTags(ctx, services.PostCommentsCacheTag(postUuid))
+}
+
+func invalidateCommentInCache(ctx context.Context, postUuid string, commentId int) {
+	err := services.DeleteFromCache(ctx, services.CommentCacheKey(postUuid, strconv.Itoa(commentId)))
+	if err != nil {
+		log.Error(fmt.Sprintf("Unable to delete comment '%v' of post '%v' from the cache", commentId, postUuid), err.Error())
+	}
+}
+
+func invalidateCacheTags(ctx context.Context, tags ...string) {
+	if len(tags) == 0 {
+		return
+	}
+	err := services.InvalidateCacheTags(ctx, tags...)
+	if err != nil {
+		log.Error(fmt.Sprintf("Unable to invalidate cache tags %v", tags), err.Error())
+	}
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	postsService "github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/posts"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/api"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	utilsEntities "github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
+)
+
+type CreateCommentInput struct {
+	// AuthorUuid is the user of the token if empty, the other one is allowed only with ImpersonateAuthor
+	AuthorUuid        string
+	ImpersonateAuthor bool
+	PostUuid          string
+	Text              string
+	LinkedCommentId   *int
+}
+
+type UpdateCommentInput struct {
+	CommentId int
+	PostUuid  string
+	Text      *string
+	State     *string
+}
+
+func CreateComment(ctx context.Context, actor Actor, input CreateCommentInput) (int, error) {
+	authorUuid, err := resolveAuthor(actor, input.AuthorUuid, input.ImpersonateAuthor, "create comment")
+	if err != nil {
+		return -1, err
+	}
+
+	isPostPublished, err := IsPostPublished(ctx, input.PostUuid)
+	if err != nil {
+		return -1, fmt.Errorf("unable to verify post state: %w", err)
+	}
+
+	if !isPostPublished {
+		return -1, validationError("Unable to create comment. Post is not published")
+	}
+
+	commentId, err := services.Instance().Posts().CreateComment(ctx, input.PostUuid, authorUuid, input.Text, input.LinkedCommentId, postsService.NewCommentsTopic)
+	if err != nil {
+		return -1, err
+	}
+
+	log.Info(fmt.Sprintf("Created comment. ID: %v. Post UUID: %v", commentId, input.PostUuid))
+
+	return commentId, nil
+}
+
+func UpdateComment(ctx context.Context, actor Actor, input UpdateCommentInput) error {
+	comment, err := services.Instance().Posts().GetPrimaryComment(ctx, input.PostUuid, input.CommentId)
+	if err != nil {
+		return err
+	}
+
+	isAllowToUpdateComment := (comment.State == utilsEntities.COMMENT_STATE_NEW && actor.UserUuid == comment.AuthorUuid) || actor.IsOwner()
+
+	if !isAllowToUpdateComment {
+		log.Info(fmt.Sprintf("Forbidden to update comment. User UUID: %v", actor.UserUuid))
+		return ErrForbidden
+	}
+
+	if input.State != nil {
+		if !actor.IsOwner() {
+			log.Info(fmt.Sprintf("Forbidden to update comment state. User UUID: %v", actor.UserUuid))
+			return ErrForbidden
+		}
+		if *input.State == utilsEntities.COMMENT_STATE_DELETED {
+			return validationError(api.DELETE_VIA_PUT_REQUEST_IS_FODBIDDEN)
+		}
+
+		possibleStates := utilsEntities.GetPossibleCommentStates()
+		if !utils.Contains(possibleStates, *input.State) {
+			return validationError(fmt.Sprintf("Unable to update comment. Wrong 'State' value. Possible values: %v", possibleStates))
+		}
+	}
+
+	queueTopicsToNotify := make([]string, 0, 1)
+	if input.State != nil {
+		queueTopicsToNotify = append(queueTopicsToNotify, postsService.UpdatedCommentsStatesTopic)
+	}
+
+	err = services.Instance().Posts().UpdateComment(ctx, actor.UserUuid, input.PostUuid, input.CommentId, input.Text, input.State, queueTopicsToNotify...)
+	if err != nil {
+		return err
+	}
+
+	invalidateCommentInCache(ctx, input.PostUuid, input.CommentId)
+
+	log.Info(fmt.Sprintf("Updated comment: %v", input))
+
+	return nil
+}
+
+// GetCommentHistory returns the comment with the texts replaced by its edits
+func GetCommentHistory(ctx context.Context, postUuid string, commentId int) (entities.Comment, []entities.CommentEdit, error) {
+	comment, err := services.Instance().Posts().GetComment(ctx, postUuid, commentId)
+	if err != nil {
+		return comment, nil, err
+	}
+
+	edits, err := services.Instance().Posts().GetCommentEdits(ctx, postUuid, commentId)
+	if err != nil {
+		return comment, nil, err
+	}
+	return comment, edits, nil
+}
+
+func DeleteComment(ctx context.Context, postUuid string, commentId int) error {
+	err := services.Instance().Posts().DeleteComment(ctx, postUuid, commentId, postsService.DeletedCommentsTopic)
+	if err != nil {
+		return err
+	}
+
+	invalidateCommentInCache(ctx, postUuid, commentId)
+
+	log.Info(fmt.Sprintf("Deleted comment. Post UUID: %v. Comment ID: %v", postUuid, commentId))
+
+	return nil
+}
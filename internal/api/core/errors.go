@@ -0,0 +1,18 @@
+package core
+
+import "errors"
+
+var ErrForbidden = errors.New("forbidden")
+
+// ValidationError means that the request is malformed, the message is safe to show to the client
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func validationError(message string) error {
+	return &ValidationError{Message: message}
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	utilsEntities "github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/db/entities"
+	"github.com/sirupsen/logrus"
+)
+
+// The posts and the comments are created by any authenticated user on behalf of themselves, the author is taken from the token.
+// Only the owner could create them on behalf of another user and only if it is requested explicitly, such requests are audited.
+// The author could update and delete the own post, the owner and the moderators could do it with any post.
+// The moderation decisions are checked by the state machine of the posts, see posts.AllowedPostStates
+
+// USER_ROLE_MODERATOR is the role of the token claims, the utils know only the owner and the residents
+const USER_ROLE_MODERATOR = "MODERATOR"
+
+// Actor is the user on whose behalf an operation is performed, the role is taken from the verified token claims.
+// There is no actor without the token, the calls without it are never trusted as the owner
+type Actor struct {
+	UserUuid string
+	Role     string
+}
+
+func (a Actor) IsOwner() bool {
+	return a.Role == utilsEntities.USER_ROLE_OWNER
+}
+
+// IsModerator is set for the owner as well
+func (a Actor) IsModerator() bool {
+	return a.IsOwner() || a.Role == USER_ROLE_MODERATOR
+}
+
+// resolveAuthor returns the author of the created entity, the given author uuid is optional unless the owner impersonates another user
+func resolveAuthor(actor Actor, authorUuid string, impersonate bool, action string) (string, error) {
+	if authorUuid == "" || authorUuid == actor.UserUuid {
+		if actor.UserUuid == "" {
+			return "", validationError("Missed 'AuthorUuid'")
+		}
+		return actor.UserUuid, nil
+	}
+	if !impersonate || !actor.IsOwner() {
+		log.Info(fmt.Sprintf("Forbidden to %v on behalf of another author. User UUID: %v. Author UUID: %v", action, actor.UserUuid, authorUuid))
+		return "", ErrForbidden
+	}
+	auditImpersonation(actor, authorUuid, action)
+	return authorUuid, nil
+}
+
+// auditImpersonation writes the audit record, it is the separate entry of the log with the fields to search by
+func auditImpersonation(actor Actor, authorUuid string, action string) {
+	log.Instance().WithFields(logrus.Fields{
+		"audit":       "impersonation",
+		"action":      action,
+		"user_uuid":   actor.UserUuid,
+		"user_role":   actor.Role,
+		"author_uuid": authorUuid,
+	}).Info(fmt.Sprintf("Owner acts on behalf of another author: %v", action))
+}
+
+func authorizeUpdatePost(actor Actor, post entities.Post, input UpdatePostInput) error {
+	if actor.IsModerator() {
+		return nil
+	}
+	if post.AuthorUuid != actor.UserUuid {
+		log.Info(fmt.Sprintf("Forbidden to update post. User UUID: %v. Post UUID: %v", actor.UserUuid, post.Uuid))
+		return ErrForbidden
+	}
+	if input.AuthorUuid != nil && *input.AuthorUuid != post.AuthorUuid {
+		log.Info(fmt.Sprintf("Forbidden to change post author. User UUID: %v. Post UUID: %v", actor.UserUuid, post.Uuid))
+		return ErrForbidden
+	}
+	return nil
+}
+
+func authorizeDeletePost(actor Actor, post entities.Post) error {
+	if actor.IsModerator() || post.AuthorUuid == actor.UserUuid {
+		return nil
+	}
+	log.Info(fmt.Sprintf("Forbidden to delete post. User UUID: %v. Post UUID: %v", actor.UserUuid, post.Uuid))
+	return ErrForbidden
+}
+
+func authorizePostHistory(actor Actor, post entities.Post) error {
+	if actor.IsModerator() || post.AuthorUuid == actor.UserUuid {
+		return nil
+	}
+	log.Info(fmt.Sprintf("Forbidden to access post revisions. User UUID: %v. Post UUID: %v", actor.UserUuid, post.Uuid))
+	return ErrForbidden
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/cache"
+	postsService "github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/posts"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/api"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	utilsEntities "github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
+	"github.com/google/uuid"
+)
+
+type CreatePostInput struct {
+	// AuthorUuid is the user of the token if empty, the other one is allowed only with ImpersonateAuthor
+	AuthorUuid        string
+	ImpersonateAuthor bool
+	Text              string
+	PreviewText       string
+	Topic             string
+	TagIds            []int
+}
+
+type UpdatePostInput struct {
+	Uuid        string
+	AuthorUuid  *string
+	Text        *string
+	PreviewText *string
+	Topic       *string
+	State       *string
+	TagIds      *[]int
+}
+
+// CreatePost creates the post with tags and returns its uuid
+func CreatePost(ctx context.Context, actor Actor, input CreatePostInput) (string, error) {
+	authorUuid, err := resolveAuthor(actor, input.AuthorUuid, input.ImpersonateAuthor, "create post")
+	if err != nil {
+		return "", err
+	}
+
+	uuid, err := uuid.NewRandom()
+	if err != nil {
+		return "", fmt.Errorf("unable to create uuid for post: %w", err)
+	}
+
+	postUuid := uuid.String()
+
+	postId, err := services.Instance().Posts().CreatePostWithTags(ctx, postUuid, authorUuid, input.Text, input.PreviewText, input.Topic, input.TagIds, postsService.NewPostsTopic)
+	if err != nil {
+		if errors.Is(err, postsService.ErrorUnknownTags) {
+			return "", validationError(fmt.Sprintf("Unable to create post. Wrong 'TagIds' value. %v", err))
+		}
+		return "", err
+	}
+
+	log.Info(fmt.Sprintf("Created post. Id: %v. Uuid: %v. TagIds: %v", postId, postUuid, input.TagIds))
+
+	return postUuid, nil
+}
+
+func UpdatePost(ctx context.Context, actor Actor, input UpdatePostInput) error {
+	if !actor.IsModerator() {
+		post, err := services.Instance().Posts().GetPrimaryPost(ctx, input.Uuid)
+		if err != nil {
+			return err
+		}
+		if err := authorizeUpdatePost(actor, post, input); err != nil {
+			return err
+		}
+	}
+
+	if input.State != nil {
+		if *input.State == utilsEntities.POST_STATE_DELETED {
+			return validationError(api.DELETE_VIA_PUT_REQUEST_IS_FODBIDDEN)
+		}
+
+		possibleStates := postsService.PostStates()
+		if !utils.Contains(possibleStates, *input.State) {
+			return validationError(fmt.Sprintf("Unable to update post. Wrong 'State' value. Possible values: %v", possibleStates))
+		}
+	}
+
+	queueTopicsToNotify := make([]string, 0, 2)
+	if input.State != nil {
+		queueTopicsToNotify = append(queueTopicsToNotify, postsService.UpdatedPostsStatesTopic)
+	}
+	if input.TagIds != nil {
+		queueTopicsToNotify = append(queueTopicsToNotify, postsService.UpdatedPostsTagsTopic)
+	}
+
+	editor := postsService.Editor{Uuid: actor.UserUuid, Moderator: actor.IsModerator()}
+	err := services.Instance().Posts().UpdatePostWithTags(ctx, editor, input.Uuid, input.AuthorUuid, input.Text, input.PreviewText, input.Topic, input.State, input.TagIds, queueTopicsToNotify...)
+	if err != nil {
+		if errors.Is(err, postsService.ErrorUnknownTags) {
+			return validationError(fmt.Sprintf("Unable to update post. Wrong 'TagIds' value. %v", err))
+		}
+		return err
+	}
+
+	log.Info(fmt.Sprintf("Updated post: %v", input))
+
+	invalidatePostsInCache(ctx, input.Uuid)
+
+	return nil
+}
+
+func DeletePost(ctx context.Context, actor Actor, postUuid string) error {
+	if !actor.IsModerator() {
+		post, err := services.Instance().Posts().GetPrimaryPost(ctx, postUuid)
+		if err != nil {
+			return err
+		}
+		if err := authorizeDeletePost(actor, post); err != nil {
+			return err
+		}
+	}
+
+	editor := postsService.Editor{Uuid: actor.UserUuid, Moderator: actor.IsModerator()}
+	err := services.Instance().Posts().DeletePost(ctx, editor, postUuid, postsService.DeletedPostsTopic)
+	if err != nil {
+		return err
+	}
+
+	invalidatePostsInCache(ctx, postUuid)
+	invalidateCommentsInCache(ctx, postUuid)
+
+	log.Info(fmt.Sprintf("Deleted post. Uuid: %v", postUuid))
+
+	return nil
+}
+
+// IsPostPublished checks the post state. Only published posts are cached, so the cache is checked first
+func IsPostPublished(ctx context.Context, postUuid string) (bool, error) {
+	cached, err := services.GetFromCache(ctx, services.PostCacheKey(postUuid, false))
+	if err != nil {
+		log.Error("Unable to read cache", err.Error())
+	}
+	if cache.IsNegativeValue(cached) {
+		return false, sql.ErrNoRows
+	}
+	if len(cached) > 0 {
+		return true, nil
+	}
+	post, err := services.Instance().Posts().GetPost(ctx, postUuid)
+	if err != nil {
+		return false, err
+	}
+	return post.State == utilsEntities.POST_STATE_PUBLISHED, nil
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/diff"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+)
+
+// The revisions of the post are shown to its author and the moderators, the drafts are not public
+
+func GetPostRevisions(ctx context.Context, actor Actor, postUuid string, limit int, beforeRevision int) ([]entities.PostRevision, error) {
+	err := checkPostHistoryAccess(ctx, actor, postUuid)
+	if err != nil {
+		return nil, err
+	}
+	return services.Instance().Posts().GetPostRevisions(ctx, postUuid, limit, beforeRevision)
+}
+
+func GetPostRevision(ctx context.Context, actor Actor, postUuid string, revision int) (entities.PostRevision, error) {
+	err := checkPostHistoryAccess(ctx, actor, postUuid)
+	if err != nil {
+		return entities.PostRevision{}, err
+	}
+	return services.Instance().Posts().GetPostRevision(ctx, postUuid, revision)
+}
+
+// DiffPostRevisions returns the unified diff of the topic, the preview text and the text of the revisions,
+// the latest revision is taken if toRevision is 0
+func DiffPostRevisions(ctx context.Context, actor Actor, postUuid string, fromRevision int, toRevision int) (string, int, error) {
+	err := checkPostHistoryAccess(ctx, actor, postUuid)
+	if err != nil {
+		return "", toRevision, err
+	}
+
+	if toRevision == 0 {
+		latest, err := services.Instance().Posts().GetPostRevisions(ctx, postUuid, 1, 0)
+		if err != nil {
+			return "", toRevision, err
+		}
+		if len(latest) == 0 {
+			return "", toRevision, sql.ErrNoRows
+		}
+		toRevision = latest[0].Revision
+	}
+
+	from, err := services.Instance().Posts().GetPostRevision(ctx, postUuid, fromRevision)
+	if err != nil {
+		return "", toRevision, err
+	}
+	to, err := services.Instance().Posts().GetPostRevision(ctx, postUuid, toRevision)
+	if err != nil {
+		return "", toRevision, err
+	}
+
+	var result strings.Builder
+	for _, field := range []struct {
+		name string
+		from string
+		to   string
+	}{
+		{name: "topic", from: from.Topic, to: to.Topic},
+		{name: "preview_text", from: from.PreviewText, to: to.PreviewText},
+		{name: "text", from: from.Text, to: to.Text},
+	} {
+		fieldDiff, err := diff.Unified(
+			fmt.Sprintf("revision/%v/%v", fromRevision, field.name),
+			fmt.Sprintf("revision/%v/%v", toRevision, field.name),
+			field.from, field.to,
+		)
+		if errors.Is(err, diff.ErrTooManyChanges) {
+			return "", toRevision, validationError(fmt.Sprintf("Unable to compare the %v of the revisions, %v", field.name, err))
+		}
+		if err != nil {
+			return "", toRevision, err
+		}
+		result.WriteString(fieldDiff)
+	}
+	return result.String(), toRevision, nil
+}
+
+// RestorePostRevision writes the content of the revision as the new one, the revisions after it are kept
+func RestorePostRevision(ctx context.Context, actor Actor, postUuid string, revision int) error {
+	err := checkPostHistoryAccess(ctx, actor, postUuid)
+	if err != nil {
+		return err
+	}
+
+	restored, err := services.Instance().Posts().GetPostRevision(ctx, postUuid, revision)
+	if err != nil {
+		return err
+	}
+
+	err = UpdatePost(ctx, actor, UpdatePostInput{
+		Uuid:        postUuid,
+		Text:        &restored.Text,
+		PreviewText: &restored.PreviewText,
+		Topic:       &restored.Topic,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info(fmt.Sprintf("Restored post revision. Post UUID: %v. Revision: %v. User UUID: %v", postUuid, revision, actor.UserUuid))
+
+	return nil
+}
+
+func checkPostHistoryAccess(ctx context.Context, actor Actor, postUuid string) error {
+	if actor.IsModerator() {
+		return nil
+	}
+	post, err := services.Instance().Posts().GetPrimaryPost(ctx, postUuid)
+	if err != nil {
+		return err
+	}
+	return authorizePostHistory(actor, post)
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+)
+
+type TagDeletionResult struct {
+	Id                 int
+	DryRun             bool
+	AffectedPostsCount int
+}
+
+func CreateTag(ctx context.Context, name string) (int, error) {
+	tagId, err := services.Instance().Posts().CreateTag(ctx, name)
+	if err != nil {
+		return -1, err
+	}
+
+	log.Info(fmt.Sprintf("Created tag. Id: %v", tagId))
+
+	return tagId, nil
+}
+
+func UpdateTag(ctx context.Context, id int, name string) error {
+	err := services.Instance().Posts().UpdateTag(ctx, id, name)
+	if err != nil {
+		return err
+	}
+
+	// the cached posts contain the tag names
+	invalidatePostsWithTagInCache(ctx, id)
+
+	log.Info(fmt.Sprintf("Updated tag. Id: %v. New name: %v", id, name))
+
+	return nil
+}
+
+// DeleteTag detaches the tag from all posts and deletes it. In dry run mode only counts the posts that would be touched
+func DeleteTag(ctx context.Context, id int, dryRun bool) (TagDeletionResult, error) {
+	result := TagDeletionResult{Id: id, DryRun: dryRun}
+
+	if dryRun {
+		_, err := services.Instance().Posts().GetPrimaryTag(ctx, id)
+		if err != nil {
+			return result, err
+		}
+		count, err := services.Instance().Posts().CountPostsWithTag(ctx, id)
+		if err != nil {
+			return result, err
+		}
+		result.AffectedPostsCount = count
+		return result, nil
+	}
+
+	postUuids, err := services.Instance().Posts().DeleteTag(ctx, id)
+	// posts could be detached from the tag before the failure
+	invalidatePostsWithTagInCache(ctx, id)
+	invalidatePostsInCache(ctx, postUuids...)
+	if err != nil {
+		return result, err
+	}
+
+	log.Info(fmt.Sprintf("Deleted tag. Id: %v. Detached from posts: %v", id, postUuids))
+
+	result.AffectedPostsCount = len(postUuids)
+	return result, nil
+}
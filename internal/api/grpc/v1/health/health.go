@@ -0,0 +1,22 @@
+package health
+
+import (
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// RegisterServiceServer registers the standard grpc.health.v1 service, the status of the server follows the readiness checks
+func RegisterServiceServer(s *grpc.Server) {
+	healthServer := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(s, healthServer)
+
+	services.Instance().Readiness().OnUpdate(func(report services.ReadinessReport) {
+		status := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+		if report.Ready {
+			status = grpc_health_v1.HealthCheckResponse_SERVING
+		}
+		healthServer.SetServingStatus("", status)
+	})
+}
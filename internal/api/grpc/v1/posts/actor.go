@@ -0,0 +1,56 @@
+package posts
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/core"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// METADATA_AUTHORIZATION carries the token of the user on whose behalf the write methods are called, e.g. "Bearer <token>"
+const METADATA_AUTHORIZATION = "authorization"
+
+// actorFromContext returns the user of the verified token, the calls without the token are rejected like the REST ones
+func actorFromContext(ctx context.Context) (core.Actor, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	token := strings.TrimPrefix(firstValue(md, METADATA_AUTHORIZATION), "Bearer ")
+	if token == "" {
+		return core.Actor{}, status.Error(codes.Unauthenticated, "Unauthorized")
+	}
+
+	result, err := services.Instance().Auth().VerifyToken(token)
+	if err != nil {
+		log.Error("Unable to verify token", err.Error())
+		return core.Actor{}, status.Error(codes.Internal, "Unable to verify token")
+	}
+	if result.IsExpired || !result.IsValid || result.Claims == nil {
+		return core.Actor{}, status.Error(codes.Unauthenticated, "Unauthorized")
+	}
+
+	return core.Actor{UserUuid: result.Claims.Id, Role: result.Claims.Role}, nil
+}
+
+// ownerFromContext is the same as app.RequiredOwnerRole of the REST routes
+func ownerFromContext(ctx context.Context) (core.Actor, error) {
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return actor, err
+	}
+	if !actor.IsOwner() {
+		return actor, status.Error(codes.PermissionDenied, "Forbidden")
+	}
+	return actor, nil
+}
+
+func firstValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
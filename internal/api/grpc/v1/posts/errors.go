@@ -0,0 +1,112 @@
+package posts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/core"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/queries"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/pagination"
+	postsService "github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/posts"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/api"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	ResourceTypePost    = "post"
+	ResourceTypeComment = "comment"
+	ResourceTypeTag     = "tag"
+)
+
+// resource identifies the entity the call operates on, it goes to the error details so the callers don't have to parse messages
+type resource struct {
+	Type string
+	Id   string
+}
+
+func postResource(postUuid string) resource {
+	return resource{Type: ResourceTypePost, Id: postUuid}
+}
+
+func commentResource(postUuid string, commentId int64) resource {
+	return resource{Type: ResourceTypeComment, Id: fmt.Sprintf("%v/%v", postUuid, commentId)}
+}
+
+func tagResource(tagId int64) resource {
+	return resource{Type: ResourceTypeTag, Id: fmt.Sprintf("%v", tagId)}
+}
+
+// toStatusError converts the errors of the core layer into gRPC status errors, unexpected errors are logged and reported as internal ones with the given message
+func toStatusError(err error, res resource, message string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var validationErr *core.ValidationError
+	var unavailableErr *postsService.ErrorShardUnavailable
+	var transitionErr *postsService.ErrorPostStateTransition
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return withDetails(codes.NotFound, api.PAGE_NOT_FOUND, res)
+	case errors.As(err, &validationErr):
+		return withDetails(codes.InvalidArgument, validationErr.Message, res, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Description: validationErr.Message}},
+		})
+	case errors.Is(err, pagination.ErrInvalidCursor):
+		return withDetails(codes.InvalidArgument, "Invalid cursor", res, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "Cursor", Description: err.Error()}},
+		})
+	case errors.Is(err, core.ErrForbidden), errors.Is(err, postsService.ErrorPostStateForbidden):
+		return withDetails(codes.PermissionDenied, "Forbidden", res)
+	case errors.As(err, &transitionErr):
+		message := fmt.Sprintf("Unable to change post state from %v to %v, allowed states: %v", transitionErr.From, transitionErr.To, strings.Join(transitionErr.Allowed, ", "))
+		return withDetails(codes.FailedPrecondition, message, res, &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{Type: "STATE", Subject: transitionErr.From, Description: message}},
+		})
+	case errors.Is(err, queries.ErrorTagDuplicateKey):
+		return withDetails(codes.AlreadyExists, "Tag with the same name already exists", res)
+	case errors.Is(err, postsService.ErrorShardReadOnly):
+		return withDetails(codes.Unavailable, "Posts are read-only for maintenance, try again later", res)
+	case errors.Is(err, context.Canceled):
+		return withDetails(codes.Canceled, "Request is canceled", res)
+	case errors.Is(err, context.DeadlineExceeded):
+		log.Info(fmt.Sprintf("%v: %v", message, err))
+		return withDetails(codes.DeadlineExceeded, "Posts are not loaded in time, try again later", res)
+	case errors.As(err, &unavailableErr):
+		log.Info(fmt.Sprintf("%v: %v", message, err))
+		return withDetails(codes.Unavailable, "Posts are temporarily unavailable, try again later", res, &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(unavailableErr.RetryAfter),
+		})
+	default:
+		log.Error(message, err.Error())
+		return withDetails(codes.Internal, message, res)
+	}
+}
+
+// withDetails builds the status with the resource info and the given details attached, e.g. errdetails.BadRequest
+func withDetails(code codes.Code, message string, res resource, details ...protoiface.MessageV1) error {
+	st := status.New(code, message)
+	info := &errdetails.ResourceInfo{
+		ResourceType: res.Type,
+		ResourceName: res.Id,
+		Description:  message,
+	}
+
+	detailed, err := st.WithDetails(append([]protoiface.MessageV1{info}, details...)...)
+	if err != nil {
+		log.Error("Unable to attach error details", err.Error())
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -2,19 +2,25 @@ package posts
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
+	"sort"
+	"time"
 
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/core"
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services"
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
-	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/api"
-	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/posts"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/metrics"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/pagination"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/tracing"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/pkg/api/grpc/v1/posts"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const defaultTagsLimit = 50
+const maxTagsLimit = 100
+
 type PostsServiceServer struct {
 	posts.UnimplementedPostsServiceServer
 }
@@ -23,58 +29,367 @@ func RegisterServiceServer(s *grpc.Server) {
 	posts.RegisterPostsServiceServer(s, &PostsServiceServer{})
 }
 
-func (s *PostsServiceServer) GetPost(ctx context.Context, in *posts.GetPostRequest) (*posts.GetPostReply, error) {
-	post, err := services.Instance().Posts().GetPostWithTags(in.GetUuid())
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf(api.PAGE_NOT_FOUND)
-	} else if err != nil {
-		return nil, err
+func (s *PostsServiceServer) GetPost(ctx context.Context, in *posts.GetPostRequest) (reply *posts.GetPostReply, err error) {
+	defer metrics.ObserveGRPC(ctx, time.Now(), &err)
+	ctx, span := tracing.StartGRPC(ctx)
+	defer tracing.EndGRPC(span, &err)
+
+	post, err := services.Instance().Posts().GetPostWithTags(ctx, in.GetUuid())
+	if err != nil {
+		return nil, toStatusError(err, postResource(in.GetUuid()), "Unable to get post")
 	}
 	return toGetPostReply(post), nil
 }
 
-func (s *PostsServiceServer) GetComment(ctx context.Context, in *posts.GetCommentRequest) (*posts.GetCommentReply, error) {
-	comment, err := services.Instance().Posts().GetComment(in.GetPostUuid(), int(in.GetId()))
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf(api.PAGE_NOT_FOUND)
-	} else if err != nil {
-		return nil, err
+func (s *PostsServiceServer) GetComment(ctx context.Context, in *posts.GetCommentRequest) (reply *posts.GetCommentReply, err error) {
+	defer metrics.ObserveGRPC(ctx, time.Now(), &err)
+	ctx, span := tracing.StartGRPC(ctx)
+	defer tracing.EndGRPC(span, &err)
+
+	comment, err := services.Instance().Posts().GetComment(ctx, in.GetPostUuid(), int(in.GetId()))
+	if err != nil {
+		return nil, toStatusError(err, commentResource(in.GetPostUuid(), in.GetId()), "Unable to get comment")
 	}
 	return toGetCommentReply(comment, in.GetPostUuid()), nil
 }
 
-func (s *PostsServiceServer) GetTag(ctx context.Context, in *posts.GetTagRequest) (*posts.GetTagReply, error) {
-	tag, err := services.Instance().Posts().GetTag(int(in.GetId()))
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf(api.PAGE_NOT_FOUND)
-	} else if err != nil {
-		return nil, err
+func (s *PostsServiceServer) GetTag(ctx context.Context, in *posts.GetTagRequest) (reply *posts.GetTagReply, err error) {
+	defer metrics.ObserveGRPC(ctx, time.Now(), &err)
+	ctx, span := tracing.StartGRPC(ctx)
+	defer tracing.EndGRPC(span, &err)
+
+	tag, err := services.Instance().Posts().GetTag(ctx, int(in.GetId()))
+	if err != nil {
+		return nil, toStatusError(err, tagResource(in.GetId()), "Unable to get tag")
 	}
 	return toGetTagReply(tag), nil
 }
 
-func (s *PostsServiceServer) GetTags(ctx context.Context, in *posts.GetTagsRequest) (*posts.GetTagsReply, error) {
-	var tagsList []entities.Tag
-	var err error
+func (s *PostsServiceServer) GetTags(ctx context.Context, in *posts.GetTagsRequest) (reply *posts.GetTagsReply, err error) {
+	defer metrics.ObserveGRPC(ctx, time.Now(), &err)
+	ctx, span := tracing.StartGRPC(ctx)
+	defer tracing.EndGRPC(span, &err)
 
-	tagsList, err = services.Instance().Posts().GetTags(int(in.GetOffset()), int(in.GetLimit()))
+	limit := in.GetLimit()
+	if limit <= 0 || limit > maxTagsLimit {
+		limit = defaultTagsLimit
+	}
 
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf(api.PAGE_NOT_FOUND)
-	} else if err != nil {
-		return nil, err
+	var tagsList []entities.Tag
+	var offset int32
+	switch {
+	case in.GetCursor() != "":
+		var after *pagination.Cursor
+		after, err = services.Instance().Cursors().Decode(in.GetCursor())
+		if err != nil {
+			return nil, toStatusError(err, resource{Type: ResourceTypeTag}, "Unable to get tags")
+		}
+		tagsList, err = services.Instance().Posts().GetTags(ctx, int(limit), after)
+	case in.GetOffset() > 0:
+		// the clients which have not moved to the cursors yet
+		offset = in.GetOffset()
+		tagsList, err = services.Instance().Posts().GetTagsWithOffset(ctx, int(offset), int(limit))
+	default:
+		tagsList, err = services.Instance().Posts().GetTags(ctx, int(limit), nil)
+	}
+	if err != nil {
+		return nil, toStatusError(err, resource{Type: ResourceTypeTag}, "Unable to get tags")
 	}
 
 	result := &posts.GetTagsReply{
-		Offset: in.Offset,
-		Limit:  in.Limit,
+		Offset: offset,
+		Limit:  limit,
 		Count:  int32(len(tagsList)),
 		Tags:   toGetTagReplies(tagsList),
 	}
+	if len(tagsList) == int(limit) {
+		result.NextCursor, err = services.Instance().Cursors().Encode(pagination.Cursor{Id: tagsList[len(tagsList)-1].Id})
+		if err != nil {
+			return nil, toStatusError(fmt.Errorf("unable to create cursor for tags: %w", err), resource{Type: ResourceTypeTag}, "Unable to get tags")
+		}
+	}
 
 	return result, nil
 }
 
+func (s *PostsServiceServer) CreatePost(ctx context.Context, in *posts.CreatePostRequest) (reply *posts.CreatePostReply, err error) {
+	defer metrics.ObserveGRPC(ctx, time.Now(), &err)
+	ctx, span := tracing.StartGRPC(ctx)
+	defer tracing.EndGRPC(span, &err)
+
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireFields(map[string]bool{
+		"Text":        in.GetText() != "",
+		"PreviewText": in.GetPreviewText() != "",
+		"Topic":       in.GetTopic() != "",
+	})
+	if err != nil {
+		return nil, toStatusError(err, resource{Type: ResourceTypePost}, "Unable to create post")
+	}
+
+	postUuid, err := core.CreatePost(ctx, actor, core.CreatePostInput{
+		AuthorUuid:        in.GetAuthorUuid(),
+		ImpersonateAuthor: in.GetImpersonateAuthor(),
+		Text:              in.GetText(),
+		PreviewText:       in.GetPreviewText(),
+		Topic:             in.GetTopic(),
+		TagIds:            toInts(in.GetTagIds()),
+	})
+	if err != nil {
+		return nil, toStatusError(err, resource{Type: ResourceTypePost}, "Unable to create post")
+	}
+	return &posts.CreatePostReply{Uuid: postUuid}, nil
+}
+
+func (s *PostsServiceServer) UpdatePost(ctx context.Context, in *posts.UpdatePostRequest) (reply *posts.UpdatePostReply, err error) {
+	defer metrics.ObserveGRPC(ctx, time.Now(), &err)
+	ctx, span := tracing.StartGRPC(ctx)
+	defer tracing.EndGRPC(span, &err)
+
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireFields(map[string]bool{"Uuid": in.GetUuid() != ""})
+	if err != nil {
+		return nil, toStatusError(err, postResource(in.GetUuid()), "Unable to update post")
+	}
+
+	input := core.UpdatePostInput{
+		Uuid:        in.GetUuid(),
+		AuthorUuid:  in.AuthorUuid,
+		Text:        in.Text,
+		PreviewText: in.PreviewText,
+		Topic:       in.Topic,
+		State:       in.State,
+	}
+	if in.GetUpdateTagIds() {
+		tagIds := toInts(in.GetTagIds())
+		input.TagIds = &tagIds
+	}
+
+	err = core.UpdatePost(ctx, actor, input)
+	if err != nil {
+		return nil, toStatusError(err, postResource(in.GetUuid()), "Unable to update post")
+	}
+	return &posts.UpdatePostReply{}, nil
+}
+
+func (s *PostsServiceServer) DeletePost(ctx context.Context, in *posts.DeletePostRequest) (reply *posts.DeletePostReply, err error) {
+	defer metrics.ObserveGRPC(ctx, time.Now(), &err)
+	ctx, span := tracing.StartGRPC(ctx)
+	defer tracing.EndGRPC(span, &err)
+
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireFields(map[string]bool{"Uuid": in.GetUuid() != ""})
+	if err != nil {
+		return nil, toStatusError(err, postResource(in.GetUuid()), "Unable to delete post")
+	}
+
+	err = core.DeletePost(ctx, actor, in.GetUuid())
+	if err != nil {
+		return nil, toStatusError(err, postResource(in.GetUuid()), "Unable to delete post")
+	}
+	return &posts.DeletePostReply{}, nil
+}
+
+func (s *PostsServiceServer) CreateComment(ctx context.Context, in *posts.CreateCommentRequest) (reply *posts.CreateCommentReply, err error) {
+	defer metrics.ObserveGRPC(ctx, time.Now(), &err)
+	ctx, span := tracing.StartGRPC(ctx)
+	defer tracing.EndGRPC(span, &err)
+
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireFields(map[string]bool{
+		"PostUuid": in.GetPostUuid() != "",
+		"Text":     in.GetText() != "",
+	})
+	if err != nil {
+		return nil, toStatusError(err, postResource(in.GetPostUuid()), "Unable to create comment")
+	}
+
+	var linkedCommentId *int
+	if in.LinkedCommentId != nil {
+		id := int(in.GetLinkedCommentId())
+		linkedCommentId = &id
+	}
+
+	commentId, err := core.CreateComment(ctx, actor, core.CreateCommentInput{
+		AuthorUuid:        in.GetAuthorUuid(),
+		ImpersonateAuthor: in.GetImpersonateAuthor(),
+		PostUuid:          in.GetPostUuid(),
+		Text:              in.GetText(),
+		LinkedCommentId:   linkedCommentId,
+	})
+	if err != nil {
+		return nil, toStatusError(err, postResource(in.GetPostUuid()), "Unable to create comment")
+	}
+	return &posts.CreateCommentReply{Id: int64(commentId)}, nil
+}
+
+func (s *PostsServiceServer) UpdateComment(ctx context.Context, in *posts.UpdateCommentRequest) (reply *posts.UpdateCommentReply, err error) {
+	defer metrics.ObserveGRPC(ctx, time.Now(), &err)
+	ctx, span := tracing.StartGRPC(ctx)
+	defer tracing.EndGRPC(span, &err)
+
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireFields(map[string]bool{
+		"Id":       in.GetId() != 0,
+		"PostUuid": in.GetPostUuid() != "",
+	})
+	if err != nil {
+		return nil, toStatusError(err, commentResource(in.GetPostUuid(), in.GetId()), "Unable to update comment")
+	}
+
+	err = core.UpdateComment(ctx, actor, core.UpdateCommentInput{
+		CommentId: int(in.GetId()),
+		PostUuid:  in.GetPostUuid(),
+		Text:      in.Text,
+		State:     in.State,
+	})
+	if err != nil {
+		return nil, toStatusError(err, commentResource(in.GetPostUuid(), in.GetId()), "Unable to update comment")
+	}
+	return &posts.UpdateCommentReply{}, nil
+}
+
+func (s *PostsServiceServer) DeleteComment(ctx context.Context, in *posts.DeleteCommentRequest) (reply *posts.DeleteCommentReply, err error) {
+	defer metrics.ObserveGRPC(ctx, time.Now(), &err)
+	ctx, span := tracing.StartGRPC(ctx)
+	defer tracing.EndGRPC(span, &err)
+
+	_, err = ownerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireFields(map[string]bool{
+		"Id":       in.GetId() != 0,
+		"PostUuid": in.GetPostUuid() != "",
+	})
+	if err != nil {
+		return nil, toStatusError(err, commentResource(in.GetPostUuid(), in.GetId()), "Unable to delete comment")
+	}
+
+	err = core.DeleteComment(ctx, in.GetPostUuid(), int(in.GetId()))
+	if err != nil {
+		return nil, toStatusError(err, commentResource(in.GetPostUuid(), in.GetId()), "Unable to delete comment")
+	}
+	return &posts.DeleteCommentReply{}, nil
+}
+
+func (s *PostsServiceServer) CreateTag(ctx context.Context, in *posts.CreateTagRequest) (reply *posts.CreateTagReply, err error) {
+	defer metrics.ObserveGRPC(ctx, time.Now(), &err)
+	ctx, span := tracing.StartGRPC(ctx)
+	defer tracing.EndGRPC(span, &err)
+
+	_, err = ownerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireFields(map[string]bool{"Name": in.GetName() != ""})
+	if err != nil {
+		return nil, toStatusError(err, resource{Type: ResourceTypeTag}, "Unable to create tag")
+	}
+
+	tagId, err := core.CreateTag(ctx, in.GetName())
+	if err != nil {
+		return nil, toStatusError(err, resource{Type: ResourceTypeTag}, "Unable to create tag")
+	}
+	return &posts.CreateTagReply{Id: int64(tagId)}, nil
+}
+
+func (s *PostsServiceServer) UpdateTag(ctx context.Context, in *posts.UpdateTagRequest) (reply *posts.UpdateTagReply, err error) {
+	defer metrics.ObserveGRPC(ctx, time.Now(), &err)
+	ctx, span := tracing.StartGRPC(ctx)
+	defer tracing.EndGRPC(span, &err)
+
+	_, err = ownerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireFields(map[string]bool{
+		"Id":   in.GetId() != 0,
+		"Name": in.GetName() != "",
+	})
+	if err != nil {
+		return nil, toStatusError(err, tagResource(in.GetId()), "Unable to update tag")
+	}
+
+	err = core.UpdateTag(ctx, int(in.GetId()), in.GetName())
+	if err != nil {
+		return nil, toStatusError(err, tagResource(in.GetId()), "Unable to update tag")
+	}
+	return &posts.UpdateTagReply{}, nil
+}
+
+func (s *PostsServiceServer) DeleteTag(ctx context.Context, in *posts.DeleteTagRequest) (reply *posts.DeleteTagReply, err error) {
+	defer metrics.ObserveGRPC(ctx, time.Now(), &err)
+	ctx, span := tracing.StartGRPC(ctx)
+	defer tracing.EndGRPC(span, &err)
+
+	_, err = ownerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireFields(map[string]bool{"Id": in.GetId() != 0})
+	if err != nil {
+		return nil, toStatusError(err, tagResource(in.GetId()), "Unable to delete tag")
+	}
+
+	result, err := core.DeleteTag(ctx, int(in.GetId()), in.GetDryRun())
+	if err != nil {
+		return nil, toStatusError(err, tagResource(in.GetId()), "Unable to delete tag")
+	}
+	return &posts.DeleteTagReply{
+		Id:                 int64(result.Id),
+		DryRun:             result.DryRun,
+		AffectedPostsCount: int64(result.AffectedPostsCount),
+	}, nil
+}
+
+// requireFields returns the validation error listing all missing fields, map values tell if the field is set
+func requireFields(fields map[string]bool) error {
+	missing := []string{}
+	for name, isSet := range fields {
+		if !isSet {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return &core.ValidationError{Message: fmt.Sprintf("Missing required fields: %v", missing)}
+}
+
+func toInts(input []int64) []int {
+	result := make([]int, 0, len(input))
+	for _, v := range input {
+		result = append(result, int(v))
+	}
+	return result
+}
+
 func toGetPostReply(post entities.PostWithTags) *posts.GetPostReply {
 	return &posts.GetPostReply{
 		Uuid:           post.Post.Uuid,
@@ -104,7 +419,7 @@ func toGetCommentReply(comment entities.Comment, postUuid string) *posts.GetComm
 	if comment.LinkedCommentId != nil {
 		linkedCommentId = fmt.Sprintf("%v", *comment.LinkedCommentId)
 	}
-	return &posts.GetCommentReply{
+	reply := &posts.GetCommentReply{
 		Id:              int64(comment.Id),
 		AuthorUuid:      comment.AuthorUuid,
 		PostUuid:        postUuid,
@@ -113,7 +428,12 @@ func toGetCommentReply(comment entities.Comment, postUuid string) *posts.GetComm
 		State:           comment.State,
 		CreateDate:      timestamppb.New(comment.CreateDate),
 		LastUpdateDate:  timestamppb.New(comment.LastUpdateDate),
+		EditCount:       int32(comment.EditCount),
+	}
+	if comment.EditedAt != nil {
+		reply.EditedAt = timestamppb.New(*comment.EditedAt)
 	}
+	return reply
 }
 
 func toGetCommentReplies(input []entities.Comment, postUuid string) []*posts.GetCommentReply {
@@ -0,0 +1,15 @@
+package actor
+
+import (
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/core"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/app"
+	"github.com/gin-gonic/gin"
+)
+
+// FromContext returns the user of the token, its claims are put into the context by app.AuthReqired
+func FromContext(c *gin.Context) core.Actor {
+	return core.Actor{
+		UserUuid: c.GetString(app.CTX_TOKEN_ID_KEY),
+		Role:     c.GetString(app.CTX_TOKEN_ROLE_KEY),
+	}
+}
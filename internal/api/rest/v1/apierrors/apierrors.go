@@ -0,0 +1,71 @@
+package apierrors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/core"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/queries"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/posts"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/api"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	"github.com/gin-gonic/gin"
+)
+
+// STATUS_CLIENT_CLOSED_REQUEST is the nginx status of the requests canceled by the client, nobody reads the response anyway
+const STATUS_CLIENT_CLOSED_REQUEST = 499
+
+// StateConflictDTO is the response to the change of the post state which is not allowed from its current state
+type StateConflictDTO struct {
+	Message       string
+	State         string
+	AllowedStates []string
+}
+
+// SendError responds with the status matching the error returned by the core layer, unexpected errors are logged and reported as internal ones with the given message
+func SendError(c *gin.Context, err error, message string) {
+	var validationErr *core.ValidationError
+	var unavailableErr *posts.ErrorShardUnavailable
+	var transitionErr *posts.ErrorPostStateTransition
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, api.PAGE_NOT_FOUND)
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, validationErr.Message)
+	case errors.Is(err, core.ErrForbidden), errors.Is(err, posts.ErrorPostStateForbidden):
+		c.JSON(http.StatusForbidden, "Forbidden")
+	case errors.As(err, &transitionErr):
+		c.JSON(http.StatusConflict, StateConflictDTO{
+			Message:       fmt.Sprintf("Unable to change post state from %v to %v", transitionErr.From, transitionErr.To),
+			State:         transitionErr.From,
+			AllowedStates: transitionErr.Allowed,
+		})
+	case errors.Is(err, queries.ErrorTagDuplicateKey):
+		c.JSON(http.StatusConflict, "Tag with the same name already exists")
+	case errors.Is(err, posts.ErrorShardReadOnly):
+		c.JSON(http.StatusServiceUnavailable, "Posts are read-only for maintenance, try again later")
+	case errors.Is(err, context.Canceled):
+		c.JSON(STATUS_CLIENT_CLOSED_REQUEST, "Request is canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		c.JSON(http.StatusGatewayTimeout, "Posts are not loaded in time, try again later")
+		log.Info(fmt.Sprintf("%v: %v", message, err))
+	case errors.As(err, &unavailableErr):
+		c.Header("Retry-After", strconv.Itoa(retryAfterSeconds(unavailableErr.RetryAfter)))
+		c.JSON(http.StatusServiceUnavailable, "Posts are temporarily unavailable, try again later")
+		log.Info(fmt.Sprintf("%v: %v", message, err))
+	default:
+		c.JSON(http.StatusInternalServerError, message)
+		log.Error(message, err.Error())
+	}
+}
+
+// retryAfterSeconds rounds the delay up to the whole seconds of the Retry-After header
+func retryAfterSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
@@ -1,66 +1,123 @@
 package comments
 
 import (
-	"database/sql"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
 
-	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/rest/v1/posts"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/core"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/rest/v1/actor"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/rest/v1/apierrors"
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services"
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/pagination"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/api"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/api/validation"
-	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/app"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
 	utilsEntities "github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/db/entities"
-	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
 	"github.com/gin-gonic/gin"
 )
 
-const NewCommentsTopic = "new_comments"
-const UpdatedCommentsStatesTopic = "updated_comments_states"
-const DeletedCommentsTopic = "deleted_comments"
-
-// TODO: implement later
-// func GetComments(c *gin.Context) {
-// 	postUuid := c.Param("uuid")
-
-// 	if postUuid == "" {
-// 		c.JSON(http.StatusBadRequest, "Missed 'uuid' parameter")
-// 		return
-// 	}
-
-// 	limitStr := c.DefaultQuery("limit", "50")
-// 	offsetStr := c.DefaultQuery("offset", "0")
-
-// 	limit, err := strconv.Atoi(limitStr)
-// 	if err != nil {
-// 		limit = 50
-// 	}
-
-// 	offset, err := strconv.Atoi(offsetStr)
-// 	if err != nil {
-// 		offset = 0
-// 	}
-
-// 	comments, err := services.Instance().Posts().GetComments(postUuid, offset, limit)
-// 	if err != nil {
-// 		c.JSON(http.StatusInternalServerError, "Unable to get comments")
-// 		log.Error("Unable to get comments", err.Error())
-// 		return
-// 	}
-
-// 	result := &CommentListDTO{
-// 		Data:   convertComments(comments, postUuid),
-// 		Count:  len(comments),
-// 		Offset: offset,
-// 		Limit:  limit,
-// 	}
-
-// 	c.JSON(http.StatusOK, result)
-// }
+const CommentsListModeFlat = "flat"
+const CommentsListModeTree = "tree"
+
+const defaultCommentsLimit = 50
+const maxCommentsLimit = 100
+const defaultCommentsDepth = 5
+const maxCommentsDepth = 10
+
+func GetComments(c *gin.Context) {
+	postUuid := c.Param("uuid")
+
+	if postUuid == "" {
+		c.JSON(http.StatusBadRequest, "Missed 'uuid' parameter")
+		return
+	}
+
+	mode := c.DefaultQuery("mode", CommentsListModeFlat)
+	if mode != CommentsListModeFlat && mode != CommentsListModeTree {
+		c.JSON(http.StatusBadRequest, fmt.Sprintf("Wrong 'mode' parameter. Possible values: %v", []string{CommentsListModeFlat, CommentsListModeTree}))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultCommentsLimit)))
+	if err != nil || limit <= 0 || limit > maxCommentsLimit {
+		limit = defaultCommentsLimit
+	}
+
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", strconv.Itoa(defaultCommentsDepth)))
+	if err != nil || depth <= 0 || depth > maxCommentsDepth {
+		depth = defaultCommentsDepth
+	}
+
+	var after *pagination.Cursor
+	cursor := c.DefaultQuery("cursor", "")
+	if cursor != "" {
+		after, err = services.Instance().Cursors().Decode(cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, "Wrong 'cursor' parameter")
+			return
+		}
+	}
+
+	if mode == CommentsListModeTree {
+		getCommentsThreads(c, postUuid, limit, depth, after)
+		return
+	}
+
+	comments, err := services.Instance().Posts().GetComments(c.Request.Context(), postUuid, limit, after)
+	if err != nil {
+		apierrors.SendError(c, err, "Unable to get comments")
+		return
+	}
+
+	result := &CommentListDTO{
+		Data:  convertComments(comments),
+		Count: len(comments),
+		Limit: limit,
+	}
+	if len(comments) == limit {
+		last := comments[len(comments)-1]
+		result.NextCursor, err = services.Instance().Cursors().Encode(pagination.Cursor{CreateDate: last.CreateDate, Id: last.Id})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, "Unable to get comments")
+			log.Error("Unable to create cursor for comments", err.Error())
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func getCommentsThreads(c *gin.Context, postUuid string, limit int, depth int, after *pagination.Cursor) {
+	// one level more than requested is loaded to find out which replies were cut off
+	comments, err := services.Instance().Posts().GetCommentsThreads(c.Request.Context(), postUuid, limit, depth+1, after)
+	if err != nil {
+		apierrors.SendError(c, err, "Unable to get comments")
+		return
+	}
+
+	threads, lastRoot := buildCommentsThreads(comments, depth)
+
+	result := &CommentThreadListDTO{
+		Data:  threads,
+		Count: len(threads),
+		Limit: limit,
+		Depth: depth,
+	}
+	if lastRoot != nil && countRoots(comments) == limit {
+		result.NextCursor, err = services.Instance().Cursors().Encode(pagination.Cursor{CreateDate: lastRoot.CreateDate, Id: lastRoot.Id})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, "Unable to get comments")
+			log.Error("Unable to create cursor for comments", err.Error())
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
 
 func GetComment(c *gin.Context) {
 	postUuid := c.Param("uuid")
@@ -82,7 +139,7 @@ func GetComment(c *gin.Context) {
 		return
 	}
 
-	cached, err := getCommentFromCache(postUuid, commentIdStr)
+	cached, err := getCommentFromCache(c.Request.Context(), postUuid, commentIdStr)
 	if err != nil {
 		log.Error("Unable to read cache", err.Error())
 	}
@@ -91,14 +148,9 @@ func GetComment(c *gin.Context) {
 		return
 	}
 
-	comment, err := services.Instance().Posts().GetComment(postUuid, commentId)
+	comment, err := services.Instance().Posts().GetComment(c.Request.Context(), postUuid, commentId)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, api.PAGE_NOT_FOUND)
-		} else {
-			c.JSON(http.StatusInternalServerError, "Unable to get comment")
-			log.Error("Unable to get comment", err.Error())
-		}
+		apierrors.SendError(c, err, "Unable to get comment")
 		return
 	}
 
@@ -106,14 +158,13 @@ func GetComment(c *gin.Context) {
 
 	commentJSON, err := json.Marshal(convertedComment)
 	if err != nil {
-		// TODO: create some daemon that catch unpublished posts
 		log.Error(fmt.Sprintf("Unable to convert comment with post uuid '%v' and id '%v' to JSON", postUuid, commentIdStr), err.Error())
 	}
 
 	result := string(commentJSON)
 
 	if convertedComment.State == utilsEntities.COMMENT_STATE_PUBLISHED {
-		err = services.PutToCache(buildCacheKey(postUuid, commentIdStr), result)
+		err = services.PutToCacheWithTags(c.Request.Context(), services.CommentCacheKey(postUuid, commentIdStr), result, services.PostCommentsCacheTag(postUuid))
 		if err != nil {
 			log.Error("Unable to put post into the cache", err.Error())
 		}
@@ -122,44 +173,52 @@ func GetComment(c *gin.Context) {
 	c.JSON(http.StatusOK, convertedComment)
 }
 
-func CreateComment(c *gin.Context) {
-	var dto CommentCreateDTO
-
-	if err := c.ShouldBindJSON(&dto); err != nil {
-		validation.SendError(c, err)
+// GetCommentHistory returns the versions of the text of the comment, it is allowed to the owner only
+func GetCommentHistory(c *gin.Context) {
+	postUuid := c.Param("uuid")
+	if postUuid == "" {
+		c.JSON(http.StatusBadRequest, "Missed 'uuid' parameter")
 		return
 	}
 
-	isPostPublished, err := posts.IsPostPublished(dto.PostUuid)
+	commentId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, "Unable to verify post state")
-		log.Error("Unable to verify post state", err.Error())
+		c.JSON(http.StatusBadRequest, "Wrong 'id' parameter")
 		return
 	}
 
-	if !isPostPublished {
-		c.JSON(http.StatusBadRequest, "Unable to create post. Post is not published")
+	comment, edits, err := core.GetCommentHistory(c.Request.Context(), postUuid, commentId)
+	if err != nil {
+		apierrors.SendError(c, err, "Unable to get comment history")
 		return
 	}
 
-	commentId, err := services.Instance().Posts().CreateComment(dto.PostUuid, dto.AuthorUuid, dto.Text, dto.LinkedCommentId)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, "Unable to create comment")
-		log.Error("Unable to create comment", err.Error())
+	c.JSON(http.StatusOK, &CommentHistoryDTO{
+		Comment:  convertComment(comment),
+		Versions: convertCommentVersions(comment, edits),
+	})
+}
+
+func CreateComment(c *gin.Context) {
+	var dto CommentCreateDTO
+
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		validation.SendError(c, err)
 		return
 	}
 
-	log.Info(fmt.Sprintf("Created comment. ID: %v. Post UUID: %v", commentId, dto.PostUuid))
-
-	comment, err := services.Instance().Posts().GetComment(dto.PostUuid, commentId)
+	commentId, err := core.CreateComment(c.Request.Context(), actor.FromContext(c), core.CreateCommentInput{
+		AuthorUuid:        dto.AuthorUuid,
+		ImpersonateAuthor: dto.ImpersonateAuthor,
+		PostUuid:          dto.PostUuid,
+		Text:              dto.Text,
+		LinkedCommentId:   dto.LinkedCommentId,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, "Unable to create comment")
-		log.Error("Unable to get comment after creation", err.Error())
+		apierrors.SendError(c, err, "Unable to create comment")
 		return
 	}
 
-	sendCommentToKafkaQueue(comment, NewCommentsTopic)
-
 	c.JSON(http.StatusCreated, commentId)
 }
 
@@ -170,68 +229,17 @@ func UpdateComment(c *gin.Context) {
 		return
 	}
 
-	comment, err := services.Instance().Posts().GetComment(dto.PostUuid, dto.CommentId)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, api.PAGE_NOT_FOUND)
-		} else {
-			c.JSON(http.StatusInternalServerError, "Unable to get comment")
-			log.Error("Unable to get comment", err.Error())
-		}
-		return
-	}
-
-	isAllowToUpdateComment := (comment.State == utilsEntities.COMMENT_STATE_NEW && app.IsSameUser(c, comment.AuthorUuid)) || app.HasOwnerRole(c)
-
-	if !isAllowToUpdateComment {
-		c.JSON(http.StatusForbidden, "Forbidden")
-		userUuidFromCtx, _ := c.Get(app.CTX_TOKEN_ID_KEY)
-		log.Info(fmt.Sprintf("Forbidden to update comment. User UUID: %v", userUuidFromCtx))
-		return
-	}
-
-	if dto.State != nil {
-		if !app.HasOwnerRole(c) {
-			c.JSON(http.StatusForbidden, "Forbidden")
-			userUuidFromCtx, _ := c.Get(app.CTX_TOKEN_ID_KEY)
-			log.Info(fmt.Sprintf("Forbidden to update comment state. User UUID: %v", userUuidFromCtx))
-			return
-		}
-		if *dto.State == utilsEntities.COMMENT_STATE_DELETED {
-			c.JSON(http.StatusBadRequest, api.DELETE_VIA_PUT_REQUEST_IS_FODBIDDEN)
-			return
-		}
-
-		possibleStates := utilsEntities.GetPossibleCommentStates()
-		if !utils.Contains(possibleStates, *dto.State) {
-			c.JSON(http.StatusBadRequest, fmt.Sprintf("Unable to update comment. Wrong 'State' value. Possible values: %v", possibleStates))
-			return
-		}
-	}
-
-	err = services.Instance().Posts().UpdateComment(dto.PostUuid, dto.CommentId, dto.Text, dto.State)
+	err := core.UpdateComment(c.Request.Context(), actor.FromContext(c), core.UpdateCommentInput{
+		CommentId: dto.CommentId,
+		PostUuid:  dto.PostUuid,
+		Text:      dto.Text,
+		State:     dto.State,
+	})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, api.PAGE_NOT_FOUND)
-		} else {
-			c.JSON(http.StatusInternalServerError, "Unable to update comment")
-			log.Error("Unable to update comment", err.Error())
-		}
+		apierrors.SendError(c, err, "Unable to update comment")
 		return
 	}
 
-	log.Info(fmt.Sprintf("Updated comment: %v", dto))
-
-	if dto.State != nil {
-		comment, err := services.Instance().Posts().GetComment(dto.PostUuid, dto.CommentId)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, "Unable to create comment")
-			log.Error("Unable to get comment after creation", err.Error())
-		} else {
-			sendCommentToKafkaQueue(comment, UpdatedCommentsStatesTopic)
-		}
-	}
-
 	c.JSON(http.StatusOK, api.DONE)
 }
 
@@ -242,27 +250,17 @@ func DeleteComment(c *gin.Context) {
 		return
 	}
 
-	err := services.Instance().Posts().DeleteComment(dto.PostUuid, dto.CommentId)
-
+	err := core.DeleteComment(c.Request.Context(), dto.PostUuid, dto.CommentId)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, api.PAGE_NOT_FOUND)
-		} else {
-			c.JSON(http.StatusInternalServerError, "Unable to delete comment")
-			log.Error("Unable to delete comment", err.Error())
-		}
+		apierrors.SendError(c, err, "Unable to delete comment")
 		return
 	}
 
-	log.Info(fmt.Sprintf("Deleted comment. Post UUID: %v. Comment ID: %v", dto.PostUuid, dto.CommentId))
-
-	sendDeletedCommentToKafkaQueue(dto.PostUuid, dto.CommentId, DeletedCommentsTopic)
-
 	c.JSON(http.StatusOK, api.DONE)
 }
 
-func getCommentFromCache(postUuid string, commentId string) (*CommentDTO, error) {
-	cached, err := services.GetFromCache(buildCacheKey(postUuid, commentId))
+func getCommentFromCache(ctx context.Context, postUuid string, commentId string) (*CommentDTO, error) {
+	cached, err := services.GetFromCache(ctx, services.CommentCacheKey(postUuid, commentId))
 	if err != nil {
 		return nil, err
 	}
@@ -277,49 +275,84 @@ func getCommentFromCache(postUuid string, commentId string) (*CommentDTO, error)
 	return nil, nil
 }
 
-func buildCacheKey(postUuid string, commentId string) string {
-	return fmt.Sprintf("post_%v_comment_%v", postUuid, commentId)
+func toComment(jsonStr string) (*CommentDTO, error) {
+	var result *CommentDTO
+	err := json.Unmarshal([]byte(jsonStr), &result)
+	if err != nil {
+		return result, fmt.Errorf("unable to unmarshal comment: %w", err)
+	}
+	return result, nil
 }
 
-func sendCommentToKafkaQueue(comment entities.Comment, queueTopics ...string) {
-	commentForQueue := entities.CommentForQueue{
-		PostUuid:   comment.PostUuid,
-		CommentId:  comment.Id,
-		CreateDate: comment.CreateDate,
-		State:      comment.State,
-	}
-	commentJSON, err := json.Marshal(commentForQueue)
-	if err != nil {
-		// TODO: create some daemon that catch unpublished posts
-		log.Error(fmt.Sprintf("Unable to convert comment '%v' to JSON", commentForQueue), err.Error())
+// buildCommentsThreads links comments into trees by LinkedCommentId. Replies deeper than maxDepth are dropped and their parents are marked by HasMoreReplies.
+// Deleted comments are kept as tombstones while they have replies, otherwise they are skipped
+func buildCommentsThreads(comments []entities.Comment, maxDepth int) ([]CommentThreadDTO, *entities.Comment) {
+	children := make(map[int][]entities.Comment)
+	var roots []entities.Comment
+	for _, comment := range comments {
+		if comment.LinkedCommentId == nil {
+			roots = append(roots, comment)
+		} else {
+			children[*comment.LinkedCommentId] = append(children[*comment.LinkedCommentId], comment)
+		}
 	}
-	for _, queueTopic := range queueTopics {
-		services.SendMessageToKafkaQueue(queueTopic, string(commentJSON))
+
+	var build func(comment entities.Comment, depth int) (CommentThreadDTO, bool)
+	build = func(comment entities.Comment, depth int) (CommentThreadDTO, bool) {
+		node := CommentThreadDTO{
+			CommentDTO: convertCommentOrTombstone(comment),
+			Replies:    make([]CommentThreadDTO, 0),
+		}
+		if depth == maxDepth {
+			node.HasMoreReplies = len(children[comment.Id]) > 0
+		} else {
+			for _, reply := range children[comment.Id] {
+				if replyNode, ok := build(reply, depth+1); ok {
+					node.Replies = append(node.Replies, replyNode)
+				}
+			}
+		}
+		isVisible := comment.State != utilsEntities.COMMENT_STATE_DELETED || len(node.Replies) > 0 || node.HasMoreReplies
+		return node, isVisible
 	}
-}
 
-func sendDeletedCommentToKafkaQueue(postUuid string, commentId int, queueTopics ...string) {
-	commentForQueue := entities.DeletedCommentForQueue{
-		PostUuid:  postUuid,
-		CommentId: commentId,
+	result := make([]CommentThreadDTO, 0, len(roots))
+	for _, root := range roots {
+		if node, ok := build(root, 1); ok {
+			result = append(result, node)
+		}
 	}
-	commentJSON, err := json.Marshal(commentForQueue)
-	if err != nil {
-		// TODO: create some daemon that catch unpublished posts
-		log.Error(fmt.Sprintf("Unable to convert comment '%v' to JSON", commentForQueue), err.Error())
+
+	if len(roots) == 0 {
+		return result, nil
 	}
-	for _, queueTopic := range queueTopics {
-		services.SendMessageToKafkaQueue(queueTopic, string(commentJSON))
+	return result, &roots[len(roots)-1]
+}
+
+func countRoots(comments []entities.Comment) int {
+	result := 0
+	for _, comment := range comments {
+		if comment.LinkedCommentId == nil {
+			result++
+		}
 	}
+	return result
 }
 
-func toComment(jsonStr string) (*CommentDTO, error) {
-	var result *CommentDTO
-	err := json.Unmarshal([]byte(jsonStr), &result)
-	if err != nil {
-		return result, fmt.Errorf("unable to unmarshal comment: %w", err)
+func convertCommentOrTombstone(comment entities.Comment) CommentDTO {
+	if comment.State != utilsEntities.COMMENT_STATE_DELETED {
+		return convertComment(comment)
+	}
+	return CommentDTO{
+		Id:              comment.Id,
+		PostUuid:        comment.PostUuid,
+		LinkedCommentId: comment.LinkedCommentId,
+		State:           comment.State,
+		CreateDate:      comment.CreateDate,
+		LastUpdateDate:  comment.LastUpdateDate,
+		EditCount:       comment.EditCount,
+		EditedAt:        comment.EditedAt,
 	}
-	return result, nil
 }
 
 func convertComments(comments []entities.Comment) []CommentDTO {
@@ -343,5 +376,28 @@ func convertComment(comment entities.Comment) CommentDTO {
 		State:           comment.State,
 		CreateDate:      comment.CreateDate,
 		LastUpdateDate:  comment.LastUpdateDate,
+		EditCount:       comment.EditCount,
+		EditedAt:        comment.EditedAt,
 	}
 }
+
+// convertCommentVersions restores the versions from the edits: the edit keeps the replaced text, the editor and the date of the next version
+func convertCommentVersions(comment entities.Comment, edits []entities.CommentEdit) []CommentVersionDTO {
+	result := make([]CommentVersionDTO, 0, len(edits)+1)
+	editorUuid, createDate := comment.AuthorUuid, comment.CreateDate
+	for _, edit := range edits {
+		result = append(result, CommentVersionDTO{
+			Version:    len(result) + 1,
+			Text:       edit.Text,
+			EditorUuid: editorUuid,
+			CreateDate: createDate,
+		})
+		editorUuid, createDate = edit.EditorUuid, edit.CreateDate
+	}
+	return append(result, CommentVersionDTO{
+		Version:    len(result) + 1,
+		Text:       comment.Text,
+		EditorUuid: editorUuid,
+		CreateDate: createDate,
+	})
+}
@@ -11,13 +11,30 @@ type CommentDTO struct {
 	State           string
 	CreateDate      time.Time
 	LastUpdateDate  time.Time
+	// EditCount and EditedAt show that the text was changed after the comment was created
+	EditCount int
+	EditedAt  *time.Time `json:"EditedAt,omitempty"`
 }
 
 type CommentListDTO struct {
-	Count  int
-	Offset int
-	Limit  int
-	Data   []CommentDTO
+	Count      int
+	Limit      int
+	NextCursor string `json:"NextCursor,omitempty"`
+	Data       []CommentDTO
+}
+
+type CommentThreadDTO struct {
+	CommentDTO
+	Replies        []CommentThreadDTO
+	HasMoreReplies bool
+}
+
+type CommentThreadListDTO struct {
+	Count      int
+	Limit      int
+	Depth      int
+	NextCursor string `json:"NextCursor,omitempty"`
+	Data       []CommentThreadDTO
 }
 
 type CommentEditDTO struct {
@@ -28,14 +45,30 @@ type CommentEditDTO struct {
 	AuthorUuid string  `json:"AuthorUuid" binding:"required"`
 }
 
+// CommentCreateDTO is created on behalf of the user of the token, the owner could set another author with ImpersonateAuthor
 type CommentCreateDTO struct {
-	AuthorUuid      string `json:"AuthorUuid" binding:"required"`
-	PostUuid        string `json:"PostUuid" binding:"required"`
-	Text            string `json:"Text" binding:"required"`
-	LinkedCommentId *int   `json:"LinkedCommentId,omitempty"`
+	AuthorUuid        string `json:"AuthorUuid,omitempty"`
+	ImpersonateAuthor bool   `json:"ImpersonateAuthor,omitempty"`
+	PostUuid          string `json:"PostUuid" binding:"required"`
+	Text              string `json:"Text" binding:"required"`
+	LinkedCommentId   *int   `json:"LinkedCommentId,omitempty"`
 }
 
 type CommentDeleteDTO struct {
 	CommentId int    `json:"CommentId" binding:"required"`
 	PostUuid  string `json:"PostId" binding:"required"`
 }
+
+// CommentVersionDTO is the text of the comment written by the editor at CreateDate, the first version is written by the author
+type CommentVersionDTO struct {
+	Version    int
+	Text       string
+	EditorUuid string
+	CreateDate time.Time
+}
+
+// CommentHistoryDTO is the comment with all versions of its text from the first one to the current one
+type CommentHistoryDTO struct {
+	Comment  CommentDTO
+	Versions []CommentVersionDTO
+}
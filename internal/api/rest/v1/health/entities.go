@@ -0,0 +1,33 @@
+package health
+
+import "time"
+
+type HealthDTO struct {
+	// Status is either ok or degraded
+	Status string
+	Shards []ShardHealthDTO
+	Tags   ShardHealthDTO
+}
+
+type ShardHealthDTO struct {
+	Name          string
+	State         string
+	Usable        bool
+	Failures      int
+	LastError     string           `json:"LastError,omitempty"`
+	LastCheckDate *time.Time       `json:"LastCheckDate,omitempty"`
+	Replicas      []ShardHealthDTO `json:"Replicas,omitempty"`
+}
+
+type ReadinessDTO struct {
+	// Status is either ready or not_ready
+	Status    string
+	CheckDate *time.Time `json:"CheckDate,omitempty"`
+	Checks    []CheckDTO
+}
+
+type CheckDTO struct {
+	Name       string
+	Ok         bool
+	DurationMs int64
+}
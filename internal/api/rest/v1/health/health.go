@@ -0,0 +1,91 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/posts"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HEALTH_STATUS_OK        = "ok"
+	HEALTH_STATUS_DEGRADED  = "degraded"
+	HEALTH_STATUS_READY     = "ready"
+	HEALTH_STATUS_NOT_READY = "not_ready"
+)
+
+// Liveness tells that the process serves the requests. The dependencies are not checked here: an unavailable database
+// makes the instance not ready, but restarting it would not help
+func Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, HEALTH_STATUS_OK)
+}
+
+// Readiness responds with the last report of the periodic checks of the dependencies, 503 if any of them is unavailable.
+// The probes don't make the checks themselves, the errors are written to the log by the checker and not shown here
+func Readiness(c *gin.Context) {
+	report := services.Instance().Readiness().Report()
+
+	result := &ReadinessDTO{
+		Status: HEALTH_STATUS_READY,
+		Checks: make([]CheckDTO, 0, len(report.Checks)),
+	}
+	if !report.Date.IsZero() {
+		result.CheckDate = &report.Date
+	}
+	for _, check := range report.Checks {
+		result.Checks = append(result.Checks, CheckDTO{
+			Name:       check.Name,
+			Ok:         check.Error == nil,
+			DurationMs: check.Duration.Milliseconds(),
+		})
+	}
+	if !report.Ready {
+		result.Status = HEALTH_STATUS_NOT_READY
+		c.JSON(http.StatusServiceUnavailable, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetShardsHealth reports the state of every shard to the owner, the status is 503 while any primary is unavailable
+func GetShardsHealth(c *gin.Context) {
+	report := services.Instance().Posts().Health()
+
+	result := &HealthDTO{
+		Status: HEALTH_STATUS_OK,
+		Shards: convertShardsHealth(report.Shards),
+		Tags:   convertShardHealth(report.Tags),
+	}
+	if report.Degraded() {
+		result.Status = HEALTH_STATUS_DEGRADED
+		c.JSON(http.StatusServiceUnavailable, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func convertShardsHealth(input []posts.ShardHealth) []ShardHealthDTO {
+	result := make([]ShardHealthDTO, 0, len(input))
+	for _, shard := range input {
+		result = append(result, convertShardHealth(shard))
+	}
+	return result
+}
+
+func convertShardHealth(input posts.ShardHealth) ShardHealthDTO {
+	result := ShardHealthDTO{
+		Name:      input.Name,
+		State:     input.State,
+		Usable:    input.Usable,
+		Failures:  input.Failures,
+		LastError: input.LastError,
+	}
+	if !input.LastCheckDate.IsZero() {
+		result.LastCheckDate = &input.LastCheckDate
+	}
+	if len(input.Replicas) > 0 {
+		result.Replicas = convertShardsHealth(input.Replicas)
+	}
+	return result
+}
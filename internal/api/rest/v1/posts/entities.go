@@ -17,14 +17,11 @@ type PostDTO struct {
 	Tags        []tags.TagDTO
 }
 
-// TODO: need to add additional service and remove paramter ShardsCount, UI should not know about shards at all
-
 type PostListDTO struct {
-	Count       int
-	Offset      int
-	Limit       int
-	ShardsCount int
-	Data        []PostDTO
+	Count      int
+	Limit      int
+	NextCursor string `json:"NextCursor,omitempty"`
+	Data       []PostDTO
 }
 
 type PostEditDTO struct {
@@ -37,14 +34,40 @@ type PostEditDTO struct {
 	TagIds      *[]int  `json:"TagIds,omitempty"`
 }
 
+// PostCreateDTO is created on behalf of the user of the token, the owner could set another author with ImpersonateAuthor
 type PostCreateDTO struct {
-	AuthorUuid  string `json:"AuthorUuid" binding:"required"`
-	Text        string `json:"Text" binding:"required"`
-	PreviewText string `json:"PreviewText" binding:"required"`
-	Topic       string `json:"Topic" binding:"required"`
-	TagIds      []int  `json:"TagIds" binding:"required"`
+	AuthorUuid        string `json:"AuthorUuid,omitempty"`
+	ImpersonateAuthor bool   `json:"ImpersonateAuthor,omitempty"`
+	Text              string `json:"Text" binding:"required"`
+	PreviewText       string `json:"PreviewText" binding:"required"`
+	Topic             string `json:"Topic" binding:"required"`
+	TagIds            []int  `json:"TagIds" binding:"required"`
 }
 
 type PostDeleteDTO struct {
 	Uuid string `json:"Uuid" binding:"required"`
 }
+
+// PostRevisionDTO is the content of the post after the change, the list of revisions does not contain the texts
+type PostRevisionDTO struct {
+	Revision    int
+	EditorUuid  string
+	Text        string `json:"Text,omitempty"`
+	PreviewText string `json:"PreviewText,omitempty"`
+	Topic       string
+	CreateDate  time.Time
+}
+
+type PostRevisionListDTO struct {
+	Count int
+	Limit int
+	// NextBefore is the value of the 'before' parameter of the next page
+	NextBefore int `json:"NextBefore,omitempty"`
+	Data       []PostRevisionDTO
+}
+
+type PostRevisionsDiffDTO struct {
+	From int
+	To   int
+	Diff string
+}
@@ -1,27 +1,31 @@
 package posts
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/core"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/rest/v1/actor"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/rest/v1/apierrors"
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/rest/v1/tags"
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/cache"
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/pagination"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/api"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/api/validation"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
 	utilsEntities "github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/db/entities"
-	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
-const NewPostsTopic = "new_posts"
-const UpdatedPostsStatesTopic = "updated_posts_states"
-const UpdatedPostsTagsTopic = "updated_posts_tags"
-const DeletedPostsTopic = "deleted_posts"
+const defaultPostsLimit = 50
+const maxPostsLimit = 100
 
 func GetPost(c *gin.Context) {
 	getPost(c, false)
@@ -31,50 +35,69 @@ func GetPostPreview(c *gin.Context) {
 	getPost(c, true)
 }
 
-func CreatePost(c *gin.Context) {
-	var dto PostCreateDTO
+func GetPosts(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", strconv.Itoa(defaultPostsLimit))
+	cursor := c.DefaultQuery("cursor", "")
 
-	if err := c.ShouldBindJSON(&dto); err != nil {
-		validation.SendError(c, err)
-		return
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 || limit > maxPostsLimit {
+		limit = defaultPostsLimit
 	}
 
-	uuid, err := uuid.NewRandom()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, "Unable to create post")
-		log.Error("unable to create uuid for post", err.Error())
-		return
+	var before *pagination.Cursor
+	if cursor != "" {
+		before, err = services.Instance().Cursors().Decode(cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, "Wrong 'cursor' param")
+			return
+		}
 	}
 
-	postUuid := uuid.String()
-
-	postId, err := services.Instance().Posts().CreatePost(postUuid, dto.AuthorUuid, dto.Text, dto.PreviewText, dto.Topic)
+	list, err := services.Instance().Posts().GetPostsFeed(c.Request.Context(), utilsEntities.POST_STATE_PUBLISHED, limit, before)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, "Unable to create post")
-		log.Error("Unable to create post", err.Error())
+		apierrors.SendError(c, err, "Unable to get posts")
 		return
 	}
 
-	log.Info(fmt.Sprintf("Created post. Id: %v. Uuid: %v", postId, postUuid))
+	result := &PostListDTO{
+		Data:  convertPostPreviews(list),
+		Count: len(list),
+		Limit: limit,
+	}
+	if len(list) == limit {
+		last := list[len(list)-1].Post
+		result.NextCursor, err = services.Instance().Cursors().Encode(pagination.Cursor{CreateDate: last.CreateDate, Uuid: last.Uuid})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, "Unable to get posts")
+			log.Error("Unable to create cursor for posts", err.Error())
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
 
-	err = services.Instance().Posts().AssignTagsToPost(postUuid, dto.TagIds)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, "Unable to create post")
-		log.Error("Unable to assign tags to post", err.Error())
+func CreatePost(c *gin.Context) {
+	var dto PostCreateDTO
+
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		validation.SendError(c, err)
 		return
 	}
 
-	log.Info(fmt.Sprintf("Assigned tags to post. TagIds: %v. Post UUID: %v", dto.TagIds, postUuid))
-
-	post, err := services.Instance().Posts().GetPostWithTags(postUuid)
+	postUuid, err := core.CreatePost(c.Request.Context(), actor.FromContext(c), core.CreatePostInput{
+		AuthorUuid:        dto.AuthorUuid,
+		ImpersonateAuthor: dto.ImpersonateAuthor,
+		Text:              dto.Text,
+		PreviewText:       dto.PreviewText,
+		Topic:             dto.Topic,
+		TagIds:            dto.TagIds,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, "Unable to create post")
-		log.Error("Unable to get post after creation", err.Error())
+		apierrors.SendError(c, err, "Unable to create post")
 		return
 	}
 
-	sendPostToKafkaQueue(post, NewPostsTopic)
-
 	c.JSON(http.StatusCreated, postUuid)
 }
 
@@ -85,93 +108,20 @@ func UpdatePost(c *gin.Context) {
 		return
 	}
 
-	if dto.State != nil {
-		if *dto.State == utilsEntities.POST_STATE_DELETED {
-			c.JSON(http.StatusBadRequest, api.DELETE_VIA_PUT_REQUEST_IS_FODBIDDEN)
-			return
-		}
-
-		possibleStates := utilsEntities.GetPossiblePostStates()
-		if !utils.Contains(possibleStates, *dto.State) {
-			c.JSON(http.StatusBadRequest, fmt.Sprintf("Unable to update post. Wrong 'State' value. Possible values: %v", possibleStates))
-			return
-		}
-	}
-
-	err := services.Instance().Posts().UpdatePost(dto.Uuid, dto.AuthorUuid, dto.Text, dto.PreviewText, dto.Topic, dto.State)
+	err := core.UpdatePost(c.Request.Context(), actor.FromContext(c), core.UpdatePostInput{
+		Uuid:        dto.Uuid,
+		AuthorUuid:  dto.AuthorUuid,
+		Text:        dto.Text,
+		PreviewText: dto.PreviewText,
+		Topic:       dto.Topic,
+		State:       dto.State,
+		TagIds:      dto.TagIds,
+	})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, api.PAGE_NOT_FOUND)
-		} else {
-			c.JSON(http.StatusInternalServerError, "Unable to update post")
-			log.Error("Unable to update post", err.Error())
-		}
+		apierrors.SendError(c, err, "Unable to update post")
 		return
 	}
 
-	log.Info(fmt.Sprintf("Updated post: %v", dto))
-
-	if dto.TagIds != nil {
-		err = services.Instance().Posts().RemoveAllTagsFromPost(dto.Uuid)
-		if err != nil && err != sql.ErrNoRows {
-			c.JSON(http.StatusInternalServerError, "Unable to update post")
-			log.Error("Unable to delete all tags from post: "+dto.Uuid, err.Error())
-			return
-		}
-		err = services.Instance().Posts().AssignTagsToPost(dto.Uuid, *dto.TagIds)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, "Unable to update post")
-			log.Error("Unable to assign tags to the post: "+dto.Uuid, err.Error())
-			return
-		}
-		log.Info(fmt.Sprintf("Assigned tags to post. TagIds: %v. Post UUID: %v", *dto.TagIds, dto.Uuid))
-	}
-
-	post, err := services.Instance().Posts().GetPostWithTags(dto.Uuid)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, "Unable to update post")
-		log.Error("Unable to get post after updating "+dto.Uuid, err.Error())
-		return
-	}
-
-	queueTopicsToNotify := make([]string, 0, 2)
-
-	if dto.State != nil {
-		queueTopicsToNotify = append(queueTopicsToNotify, UpdatedPostsStatesTopic)
-	}
-
-	if dto.TagIds != nil {
-		if post.Post.State == utilsEntities.POST_STATE_PUBLISHED {
-			// then update preview and post at cache
-			postJSON, err := json.Marshal(convertPost(post))
-			if err != nil {
-				// TODO: create some daemon that catch unpublished posts
-				log.Error(fmt.Sprintf("Unable to convert post with uuid '%v' to JSON", post.Post.Uuid), err.Error())
-			}
-
-			err = services.PutToCache(buildCacheKey(post.Post.Uuid, false), string(postJSON))
-			if err != nil {
-				log.Error("Unable to put post into the cache", err.Error())
-			}
-
-			postJSON, err = json.Marshal(convertPostPreview(post))
-			if err != nil {
-				// TODO: create some daemon that catch unpublished posts
-				log.Error(fmt.Sprintf("Unable to convert post with uuid '%v' to JSON", post.Post.Uuid), err.Error())
-			}
-
-			err = services.PutToCache(buildCacheKey(post.Post.Uuid, true), string(postJSON))
-			if err != nil {
-				log.Error("Unable to put post into the cache", err.Error())
-			}
-		}
-		queueTopicsToNotify = append(queueTopicsToNotify, UpdatedPostsTagsTopic)
-	}
-
-	if len(queueTopicsToNotify) != 0 {
-		sendPostToKafkaQueue(post, queueTopicsToNotify...)
-	}
-
 	c.JSON(http.StatusOK, api.DONE)
 }
 
@@ -182,28 +132,12 @@ func DeletePost(c *gin.Context) {
 		return
 	}
 
-	err := services.Instance().Posts().RemoveAllTagsFromPost(post.Uuid)
-	if err != nil && err != sql.ErrNoRows {
-		c.JSON(http.StatusInternalServerError, "Unable to delete post")
-		log.Error("Unable to remove tags from posts", err.Error())
-		return
-	}
-
-	err = services.Instance().Posts().DeletePost(post.Uuid)
+	err := core.DeletePost(c.Request.Context(), actor.FromContext(c), post.Uuid)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, api.PAGE_NOT_FOUND)
-		} else {
-			c.JSON(http.StatusInternalServerError, "Unable to delete post")
-			log.Error("Unable to delete post", err.Error())
-		}
+		apierrors.SendError(c, err, "Unable to delete post")
 		return
 	}
 
-	services.SendMessageToKafkaQueue(DeletedPostsTopic, post.Uuid)
-
-	log.Info(fmt.Sprintf("Deleted post. Uuid: %v", post.Uuid))
-
 	c.JSON(http.StatusOK, api.DONE)
 }
 
@@ -215,26 +149,43 @@ func getPost(c *gin.Context, isPreview bool) {
 		return
 	}
 
-	cached, err := getPostFromCache(postUuid, isPreview)
+	loaded, err := services.GetOrLoadFromCache(c.Request.Context(), services.PostCacheKey(postUuid, isPreview), func(ctx context.Context) (cache.LoadResult, error) {
+		return loadPost(ctx, postUuid, isPreview)
+	})
 	if err != nil {
-		log.Error("Unable to read cache", err.Error())
+		apierrors.SendError(c, err, "Unable to get post")
+		return
 	}
-	if cached != nil {
-		c.JSON(http.StatusOK, cached)
+	if loaded.NotFound {
+		c.JSON(http.StatusNotFound, api.PAGE_NOT_FOUND)
 		return
 	}
+	if loaded.Stale {
+		// the shard of the post is unavailable, the last cached copy is returned
+		c.Header("Warning", `110 - "Response is Stale"`)
+	}
 
-	post, err := services.Instance().Posts().GetPostWithTags(postUuid)
+	post, err := toPost(loaded.Value)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, api.PAGE_NOT_FOUND)
-		} else {
-			c.JSON(http.StatusInternalServerError, "Unable to get post")
-			log.Error("Unable to get post", err.Error())
-		}
+		c.JSON(http.StatusInternalServerError, "Unable to get post")
+		log.Error("Unable to get post", err.Error())
 		return
 	}
 
+	c.JSON(http.StatusOK, post)
+}
+
+// loadPost reads the post from the shard, only published posts are cached
+func loadPost(ctx context.Context, postUuid string, isPreview bool) (cache.LoadResult, error) {
+	post, err := services.Instance().Posts().GetPostWithTags(ctx, postUuid)
+	if errors.Is(err, sql.ErrNoRows) {
+		// the negative entry is tagged as well, so the post created or restored later is not hidden by it
+		return cache.LoadResult{NotFound: true, Tags: []string{services.PostCacheTag(postUuid)}}, nil
+	}
+	if err != nil {
+		return cache.LoadResult{}, err
+	}
+
 	var convertedPost PostDTO
 
 	if isPreview {
@@ -243,61 +194,21 @@ func getPost(c *gin.Context, isPreview bool) {
 		convertedPost = convertPost(post)
 	}
 
-	if convertedPost.State == utilsEntities.POST_STATE_PUBLISHED {
-		postJSON, err := json.Marshal(convertedPost)
-		if err != nil {
-			// TODO: create some daemon that catch unpublished posts
-			log.Error(fmt.Sprintf("Unable to convert post with uuid '%v' to JSON", post.Post.Uuid), err.Error())
-		}
-
-		result := string(postJSON)
-
-		err = services.PutToCache(buildCacheKey(post.Post.Uuid, isPreview), result)
-		if err != nil {
-			log.Error("Unable to put post into the cache", err.Error())
-		}
-	}
-
-	c.JSON(http.StatusOK, convertedPost)
-}
-
-func IsPostPublished(postUuid string) (bool, error) {
-	cached, err := getPostFromCache(postUuid, false)
+	postJSON, err := json.Marshal(convertedPost)
 	if err != nil {
-		log.Error("Unable to read cache", err.Error())
+		return cache.LoadResult{}, fmt.Errorf("unable to convert post with uuid '%v' to JSON: %w", post.Post.Uuid, err)
 	}
-	if cached != nil {
-		return cached.State == utilsEntities.POST_STATE_PUBLISHED, nil
-	}
-	post, err := services.Instance().Posts().GetPost(postUuid)
-	if err != nil {
-		log.Error("Unable to get post", err.Error())
-		return false, err
-	}
-	return post.State == utilsEntities.POST_STATE_PUBLISHED, nil
-}
 
-func getPostFromCache(postUuid string, isPreview bool) (*PostDTO, error) {
-	cached, err := services.GetFromCache(buildCacheKey(postUuid, isPreview))
-	if err != nil {
-		return nil, err
-	}
-	if len(cached) > 0 {
-		result, err := toPost(cached)
-		if err != nil {
-			return nil, err
-		} else {
-			return result, nil
-		}
+	cacheTags := []string{services.PostCacheTag(post.Post.Uuid)}
+	for _, tag := range post.Tags {
+		cacheTags = append(cacheTags, services.PostsWithTagCacheTag(tag.Id))
 	}
-	return nil, nil
-}
 
-func buildCacheKey(postUuid string, isPreview bool) string {
-	if isPreview {
-		return fmt.Sprintf("post_preview_%v", postUuid)
-	}
-	return fmt.Sprintf("post_%v", postUuid)
+	return cache.LoadResult{
+		Value:      string(postJSON),
+		DoNotCache: convertedPost.State != utilsEntities.POST_STATE_PUBLISHED,
+		Tags:       cacheTags,
+	}, nil
 }
 
 func toPost(jsonStr string) (*PostDTO, error) {
@@ -309,24 +220,6 @@ func toPost(jsonStr string) (*PostDTO, error) {
 	return result, nil
 }
 
-func sendPostToKafkaQueue(post entities.PostWithTags, queueTopics ...string) {
-	postWithTagsForQueue := entities.PostWithTagsForQueue{
-		PostUuid:   post.Post.Uuid,
-		AuthorUuid: post.Post.AuthorUuid,
-		CreateDate: post.Post.CreateDate,
-		State:      post.Post.State,
-		TagIds:     post.TagIds,
-	}
-	postJSON, err := json.Marshal(postWithTagsForQueue)
-	if err != nil {
-		// TODO: create some daemon that catch unpublished posts
-		log.Error(fmt.Sprintf("Unable to convert post with uuid '%v' to JSON", post.Post.Uuid), err.Error())
-	}
-	for _, queueTopic := range queueTopics {
-		services.SendMessageToKafkaQueue(queueTopic, string(postJSON))
-	}
-}
-
 func convertPost(input entities.PostWithTags) PostDTO {
 	return PostDTO{
 		Uuid:        input.Post.Uuid,
@@ -340,6 +233,14 @@ func convertPost(input entities.PostWithTags) PostDTO {
 	}
 }
 
+func convertPostPreviews(input []entities.PostWithTags) []PostDTO {
+	result := make([]PostDTO, 0, len(input))
+	for _, p := range input {
+		result = append(result, convertPostPreview(p))
+	}
+	return result
+}
+
 func convertPostPreview(input entities.PostWithTags) PostDTO {
 	return PostDTO{
 		Uuid:        input.Post.Uuid,
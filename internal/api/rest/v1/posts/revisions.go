@@ -0,0 +1,134 @@
+package posts
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/core"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/rest/v1/actor"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/rest/v1/apierrors"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/api"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultPostRevisionsLimit = 20
+const maxPostRevisionsLimit = 100
+
+func GetPostRevisions(c *gin.Context) {
+	postUuid := c.Param("uuid")
+	if postUuid == "" {
+		c.JSON(http.StatusBadRequest, "Missed 'uuid' param")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPostRevisionsLimit)))
+	if err != nil || limit <= 0 || limit > maxPostRevisionsLimit {
+		limit = defaultPostRevisionsLimit
+	}
+
+	before, err := strconv.Atoi(c.DefaultQuery("before", "0"))
+	if err != nil || before < 0 {
+		c.JSON(http.StatusBadRequest, "Wrong 'before' param")
+		return
+	}
+
+	list, err := core.GetPostRevisions(c.Request.Context(), actor.FromContext(c), postUuid, limit, before)
+	if err != nil {
+		apierrors.SendError(c, err, "Unable to get post revisions")
+		return
+	}
+
+	result := &PostRevisionListDTO{
+		Data:  make([]PostRevisionDTO, 0, len(list)),
+		Count: len(list),
+		Limit: limit,
+	}
+	for _, r := range list {
+		result.Data = append(result.Data, convertPostRevision(r))
+	}
+	if len(list) == limit {
+		result.NextBefore = list[len(list)-1].Revision
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func GetPostRevision(c *gin.Context) {
+	postUuid, revision, ok := parsePostRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	result, err := core.GetPostRevision(c.Request.Context(), actor.FromContext(c), postUuid, revision)
+	if err != nil {
+		apierrors.SendError(c, err, "Unable to get post revision")
+		return
+	}
+
+	c.JSON(http.StatusOK, convertPostRevision(result))
+}
+
+// DiffPostRevisions compares the revision with the one given by the 'to' param, the latest revision by default
+func DiffPostRevisions(c *gin.Context) {
+	postUuid, revision, ok := parsePostRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	to, err := strconv.Atoi(c.DefaultQuery("to", "0"))
+	if err != nil || to < 0 {
+		c.JSON(http.StatusBadRequest, "Wrong 'to' param")
+		return
+	}
+
+	diff, to, err := core.DiffPostRevisions(c.Request.Context(), actor.FromContext(c), postUuid, revision, to)
+	if err != nil {
+		apierrors.SendError(c, err, "Unable to diff post revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, &PostRevisionsDiffDTO{From: revision, To: to, Diff: diff})
+}
+
+func RestorePostRevision(c *gin.Context) {
+	postUuid, revision, ok := parsePostRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	err := core.RestorePostRevision(c.Request.Context(), actor.FromContext(c), postUuid, revision)
+	if err != nil {
+		apierrors.SendError(c, err, "Unable to restore post revision")
+		return
+	}
+
+	c.JSON(http.StatusOK, api.DONE)
+}
+
+func parsePostRevisionParams(c *gin.Context) (string, int, bool) {
+	postUuid := c.Param("uuid")
+	if postUuid == "" {
+		c.JSON(http.StatusBadRequest, "Missed 'uuid' param")
+		return "", 0, false
+	}
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision <= 0 {
+		c.JSON(http.StatusBadRequest, "Wrong 'revision' param")
+		return "", 0, false
+	}
+
+	return postUuid, revision, true
+}
+
+func convertPostRevision(input entities.PostRevision) PostRevisionDTO {
+	return PostRevisionDTO{
+		Revision:    input.Revision,
+		EditorUuid:  input.EditorUuid,
+		Text:        input.Text,
+		PreviewText: input.PreviewText,
+		Topic:       input.Topic,
+		CreateDate:  input.CreateDate,
+	}
+}
@@ -6,10 +6,10 @@ type TagDTO struct {
 }
 
 type TagListDTO struct {
-	Count  int
-	Offset int
-	Limit  int
-	Data   []TagDTO
+	Count      int
+	Limit      int
+	NextCursor string `json:"NextCursor,omitempty"`
+	Data       []TagDTO
 }
 
 type TagEditDTO struct {
@@ -25,6 +25,12 @@ type TagDeleteDTO struct {
 	Id int `json:"Id" binding:"required"`
 }
 
+type TagDeletionResultDTO struct {
+	Id                 int
+	DryRun             bool
+	AffectedPostsCount int
+}
+
 type PostTagConnectionDTO struct {
 	PostUuid string `json:"PostUuid" binding:"required"`
 	TagIds   []int  `json:"TagIds" binding:"required"`
@@ -1,46 +1,60 @@
 package tags
 
 import (
-	"database/sql"
-	"fmt"
 	"net/http"
 	"strconv"
 
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/core"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/rest/v1/apierrors"
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services"
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/pagination"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/api"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/api/validation"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
 	"github.com/gin-gonic/gin"
 )
 
+const defaultTagsLimit = 50
+const maxTagsLimit = 100
+
 func GetTags(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "50")
-	offsetStr := c.DefaultQuery("offset", "0")
+	limitStr := c.DefaultQuery("limit", strconv.Itoa(defaultTagsLimit))
+	cursor := c.DefaultQuery("cursor", "")
 
 	limit, err := strconv.Atoi(limitStr)
-	if err != nil {
-		limit = 50
+	if err != nil || limit <= 0 || limit > maxTagsLimit {
+		limit = defaultTagsLimit
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil {
-		offset = 0
+	var after *pagination.Cursor
+	if cursor != "" {
+		after, err = services.Instance().Cursors().Decode(cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, "Wrong 'cursor' param")
+			return
+		}
 	}
 
 	var list []entities.Tag
-	list, err = services.Instance().Posts().GetTags(offset, limit)
+	list, err = services.Instance().Posts().GetTags(c.Request.Context(), limit, after)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, "Unable to get tags")
-		log.Error("Unable to get tags", err.Error())
+		apierrors.SendError(c, err, "Unable to get tags")
 		return
 	}
 
 	result := &TagListDTO{
-		Data:   ConvertTags(list),
-		Count:  len(list),
-		Offset: offset,
-		Limit:  limit,
+		Data:  ConvertTags(list),
+		Count: len(list),
+		Limit: limit,
+	}
+	if len(list) == limit {
+		result.NextCursor, err = services.Instance().Cursors().Encode(pagination.Cursor{Id: list[len(list)-1].Id})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, "Unable to get tags")
+			log.Error("Unable to create cursor for tags", err.Error())
+			return
+		}
 	}
 
 	c.JSON(http.StatusOK, result)
@@ -61,14 +75,9 @@ func GetTag(c *gin.Context) {
 		return
 	}
 
-	tag, err := services.Instance().Posts().GetTag(tagId)
+	tag, err := services.Instance().Posts().GetTag(c.Request.Context(), tagId)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, api.PAGE_NOT_FOUND)
-		} else {
-			c.JSON(http.StatusInternalServerError, "Unable to get tag")
-			log.Error("Unable to get tag", err.Error())
-		}
+		apierrors.SendError(c, err, "Unable to get tag")
 		return
 	}
 
@@ -83,15 +92,12 @@ func CreateTag(c *gin.Context) {
 		return
 	}
 
-	tagId, err := services.Instance().Posts().CreateTag(dto.Name)
+	tagId, err := core.CreateTag(c.Request.Context(), dto.Name)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, "Unable to create tag")
-		log.Error("Unable to create tag", err.Error())
+		apierrors.SendError(c, err, "Unable to create tag")
 		return
 	}
 
-	log.Info(fmt.Sprintf("Created tag. Id: %v", tagId))
-
 	c.JSON(http.StatusCreated, tagId)
 }
 
@@ -102,22 +108,32 @@ func UpdateTag(c *gin.Context) {
 		return
 	}
 
-	err := services.Instance().Posts().UpdateTag(dto.Id, dto.Name)
+	err := core.UpdateTag(c.Request.Context(), dto.Id, dto.Name)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, api.PAGE_NOT_FOUND)
-		} else {
-			c.JSON(http.StatusInternalServerError, "Unable to update tag")
-			log.Error("Unable to update tag", err.Error())
-		}
+		apierrors.SendError(c, err, "Unable to update tag")
 		return
 	}
 
-	log.Info(fmt.Sprintf("Updated tag. Id: %v. New name: %v", dto.Id, dto.Name))
-
 	c.JSON(http.StatusOK, api.DONE)
 }
 
+// DeleteTag detaches the tag from all posts and deletes it. With 'dryRun=true' query param only reports how many posts would be touched
+func DeleteTag(c *gin.Context) {
+	var dto TagDeleteDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		validation.SendError(c, err)
+		return
+	}
+
+	result, err := core.DeleteTag(c.Request.Context(), dto.Id, c.DefaultQuery("dryRun", "false") == "true")
+	if err != nil {
+		apierrors.SendError(c, err, "Unable to delete tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, TagDeletionResultDTO{Id: result.Id, DryRun: result.DryRun, AffectedPostsCount: result.AffectedPostsCount})
+}
+
 func ConvertTags(input []entities.Tag) []TagDTO {
 	if input == nil {
 		return []TagDTO{}
@@ -4,18 +4,23 @@ import (
 	"fmt"
 	"net/http"
 
+	healthGrpcApi "github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/grpc/v1/health"
 	postsGrpcApi "github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/grpc/v1/posts"
 	commentsRestApi "github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/rest/v1/comments"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/rest/v1/health"
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/rest/v1/ping"
 	postsRestApi "github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/rest/v1/posts"
 	tagsRestApi "github.com/ArtemVoronov/indefinite-studies-posts-service/internal/api/rest/v1/tags"
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/metrics"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/tracing"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/app"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/auth"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
 	"github.com/gin-contrib/expvar"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 )
@@ -47,6 +52,8 @@ func createRestApi(logger *logrus.Logger) *gin.Engine {
 	gin.SetMode(app.Mode())
 	router.Use(app.Cors())
 	router.Use(app.NewLoggerMiddleware(logger))
+	router.Use(metrics.HTTPMiddleware())
+	router.Use(tracing.HTTPMiddleware("/healthz", "/readyz", "/metrics"))
 	router.Use(gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
 		if err, ok := recovered.(string); ok {
 			c.String(http.StatusInternalServerError, fmt.Sprintf("error: %s", err))
@@ -54,10 +61,16 @@ func createRestApi(logger *logrus.Logger) *gin.Engine {
 		c.AbortWithStatus(http.StatusInternalServerError)
 	}))
 
+	router.GET("/healthz", health.Liveness)
+	router.GET("/readyz", health.Readiness)
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	v1 := router.Group("/api/v1")
 
 	v1.GET("/posts/ping", ping.Ping)
+	v1.GET("/posts", postsRestApi.GetPosts)
 	v1.GET("/posts/:uuid", postsRestApi.GetPost)
+	v1.GET("/posts/:uuid/comments", commentsRestApi.GetComments)
 	v1.GET("/posts/:uuid/comments/:id", commentsRestApi.GetComment)
 	v1.GET("/posts/tags", tagsRestApi.GetTags)
 	v1.GET("/posts/tags/:id", tagsRestApi.GetTag)
@@ -69,20 +82,25 @@ func createRestApi(logger *logrus.Logger) *gin.Engine {
 	{
 		authorized.GET("/posts/debug/vars", app.RequiredOwnerRole(), expvar.Handler())
 		authorized.GET("/posts/safe-ping", app.RequiredOwnerRole(), ping.SafePing)
+		authorized.GET("/posts/health/shards", app.RequiredOwnerRole(), health.GetShardsHealth)
 
-		// TODO: after allowing to create posts for others need to add rule: ONLY OWNER and MODERATOR could change states from ON_MODERATION -> PUBLISHED
-		// TODO: after allowing to create posts for others need to add rule: ONLY OWNER and MODERATOR or author of post could update it
-		// TODO: after allowing to create posts for others need to add rule: ONLY OWNER and MODERATOR or author of post could delete it
-		authorized.POST("/posts/", app.RequiredOwnerRole(), postsRestApi.CreatePost)
-		authorized.PUT("/posts/", app.RequiredOwnerRole(), postsRestApi.UpdatePost)
-		authorized.DELETE("/posts/", app.RequiredOwnerRole(), postsRestApi.DeletePost)
+		// the authors and the moderators are checked by the posts policy, see core.Actor
+		authorized.POST("/posts/", postsRestApi.CreatePost)
+		authorized.PUT("/posts/", postsRestApi.UpdatePost)
+		authorized.DELETE("/posts/", postsRestApi.DeletePost)
+		authorized.GET("/posts/:uuid/revisions", postsRestApi.GetPostRevisions)
+		authorized.GET("/posts/:uuid/revisions/:revision", postsRestApi.GetPostRevision)
+		authorized.GET("/posts/:uuid/revisions/:revision/diff", postsRestApi.DiffPostRevisions)
+		authorized.POST("/posts/:uuid/revisions/:revision/restore", postsRestApi.RestorePostRevision)
 
 		authorized.POST("/posts/comments", commentsRestApi.CreateComment)
 		authorized.PUT("/posts/comments", commentsRestApi.UpdateComment)
 		authorized.DELETE("/posts/comments", app.RequiredOwnerRole(), commentsRestApi.DeleteComment)
+		authorized.GET("/posts/:uuid/comments/:id/history", app.RequiredOwnerRole(), commentsRestApi.GetCommentHistory)
 
 		authorized.POST("/posts/tags/", app.RequiredOwnerRole(), tagsRestApi.CreateTag)
 		authorized.PUT("/posts/tags/", app.RequiredOwnerRole(), tagsRestApi.UpdateTag)
+		authorized.DELETE("/posts/tags/", app.RequiredOwnerRole(), tagsRestApi.DeleteTag)
 	}
 
 	return router
@@ -90,6 +108,7 @@ func createRestApi(logger *logrus.Logger) *gin.Engine {
 
 func createGrpcApi(s *grpc.Server) {
 	postsGrpcApi.RegisterServiceServer(s)
+	healthGrpcApi.RegisterServiceServer(s)
 }
 
 func authenicate(token string) (*auth.VerificationResult, error) {
@@ -1,10 +1,62 @@
 package services
 
-func GetFromCache(key string) (string, error) {
-	return Instance().Cache().Get(key)
+import (
+	"context"
+	"fmt"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/cache"
+)
+
+func GetFromCache(ctx context.Context, key string) (string, error) {
+	return Instance().Cache().Get(ctx, key)
+}
+
+func PutToCache(ctx context.Context, key, value string) error {
+	cache := Instance().Cache()
+	return cache.Set(ctx, key, value, cache.PostsTTL)
 }
 
-func PutToCache(key, value string) error {
+// PutToCacheWithTags puts the value and registers its key at the tags, see InvalidateCacheTags
+func PutToCacheWithTags(ctx context.Context, key, value string, tags ...string) error {
 	cache := Instance().Cache()
-	return cache.Set(key, value, cache.PostsTTL)
+	return cache.SetWithTags(ctx, key, value, cache.PostsTTL, tags...)
+}
+
+// GetOrLoadFromCache reads the key, on miss the concurrent loads of the same key are coalesced, see RedisCacheService.GetOrLoad
+func GetOrLoadFromCache(ctx context.Context, key string, load cache.Loader) (cache.LoadResult, error) {
+	return Instance().Cache().GetOrLoad(ctx, key, load)
+}
+
+func InvalidateCacheTags(ctx context.Context, tags ...string) error {
+	return Instance().Cache().InvalidateTags(ctx, tags...)
+}
+
+func DeleteFromCache(ctx context.Context, keys ...string) error {
+	return Instance().Cache().Delete(ctx, keys...)
+}
+
+func PostCacheKey(postUuid string, isPreview bool) string {
+	if isPreview {
+		return fmt.Sprintf("post_preview_%v", postUuid)
+	}
+	return fmt.Sprintf("post_%v", postUuid)
+}
+
+func CommentCacheKey(postUuid string, commentId string) string {
+	return fmt.Sprintf("post_%v_comment_%v", postUuid, commentId)
+}
+
+// PostCacheTag marks the full post and its preview
+func PostCacheTag(postUuid string) string {
+	return fmt.Sprintf("post_%v", postUuid)
+}
+
+// PostCommentsCacheTag marks all comments of the post
+func PostCommentsCacheTag(postUuid string) string {
+	return fmt.Sprintf("post_%v_comments", postUuid)
+}
+
+// PostsWithTagCacheTag marks all posts which are cached together with the tag
+func PostsWithTagCacheTag(tagId int) string {
+	return fmt.Sprintf("posts_with_tag_%v", tagId)
 }
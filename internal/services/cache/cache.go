@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	BackendRedis  = "redis"
+	BackendMemory = "memory"
+	BackendNone   = "none"
+)
+
+// Cache is the storage behind CacheService. Missing keys are returned as empty strings without errors
+type Cache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string, expiration time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// TTL returns the remaining time to live of the key, zero if the key is missing or has no expiration
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	// SetWithTags puts the value and registers its key at the given tags, so the key could be deleted later by any of them
+	SetWithTags(ctx context.Context, key, value string, expiration time.Duration, tags ...string) error
+	// InvalidateTags deletes all keys registered at the given tags and the tags themselves
+	InvalidateTags(ctx context.Context, tags ...string) error
+	Shutdown() error
+}
+
+// CacheService adds the read-through loading on top of the selected backend, see GetOrLoad
+type CacheService struct {
+	Cache
+	PostsTTL    time.Duration
+	NegativeTTL time.Duration
+	// StaleTTL is how long the stale copy of the loaded value is kept, zero disables the stale copies. They are disabled by default,
+	// every copy takes as much memory of the cache as the value itself
+	StaleTTL         time.Duration
+	EarlyRefreshBeta float64
+	loads            singleflight.Group
+	loadDuration     atomic.Int64
+	// guards are the loads in progress by key, see loadGuard
+	guardsMu sync.Mutex
+	guards   map[string]*loadGuard
+}
+
+// CreateCacheService creates the backend selected by CACHE_BACKEND, redis by default
+func CreateCacheService() (*CacheService, error) {
+	var backend Cache
+	switch name := utils.EnvVarDefault("CACHE_BACKEND", BackendRedis); name {
+	case BackendRedis:
+		backend = CreateRedisCacheService()
+	case BackendMemory:
+		backend = CreateMemoryCacheService()
+	case BackendNone:
+		backend = CreateNoneCacheService()
+	default:
+		return nil, fmt.Errorf("unknown cache backend '%v', possible values: %v", name, []string{BackendRedis, BackendMemory, BackendNone})
+	}
+
+	earlyRefreshBeta, err := strconv.ParseFloat(utils.EnvVarDefault("CACHE_EARLY_REFRESH_BETA", "0"), 64)
+	if err != nil || earlyRefreshBeta < 0 {
+		earlyRefreshBeta = 0
+	}
+
+	result := &CacheService{
+		Cache:            backend,
+		PostsTTL:         utils.EnvVarDurationDefault("CACHE_POSTS_TTL_IN_MINUTES", time.Minute, 10*time.Minute),
+		NegativeTTL:      utils.EnvVarDurationDefault("CACHE_NEGATIVE_TTL_IN_SECONDS", time.Second, 30*time.Second),
+		StaleTTL:         utils.EnvVarDurationDefault("CACHE_STALE_TTL_IN_MINUTES", time.Minute, 0),
+		EarlyRefreshBeta: earlyRefreshBeta,
+	}
+	if source, ok := backend.(invalidationSource); ok {
+		source.OnInvalidation(result.markInvalidated)
+	}
+	return result, nil
+}
+
+// Invalidation lists the deleted keys and the invalidated tags, All means that any key could be invalidated, e.g. the messages were lost
+type Invalidation struct {
+	Keys []string
+	Tags []string
+	All  bool
+}
+
+// invalidationSource is implemented by the backends shared by the instances, they report the invalidations made by the other instances.
+// The overwrites of the keys are not reported, they don't make the loads in progress stale
+type invalidationSource interface {
+	OnInvalidation(handler func(Invalidation))
+}
+
+// loadGuard is registered for the load in progress. The invalidation of its key or of any tag of the loaded value made during the load
+// keeps the value from being stored, it could be read before the change which invalidated it
+type loadGuard struct {
+	invalidated bool
+	// tags are invalidated during the load, the tags of the value are known after the load only
+	tags map[string]bool
+}
+
+func (s *CacheService) startLoad(key string) *loadGuard {
+	s.guardsMu.Lock()
+	defer s.guardsMu.Unlock()
+	if s.guards == nil {
+		s.guards = make(map[string]*loadGuard)
+	}
+	guard := &loadGuard{tags: make(map[string]bool)}
+	s.guards[key] = guard
+	return guard
+}
+
+func (s *CacheService) endLoad(key string, guard *loadGuard) {
+	s.guardsMu.Lock()
+	defer s.guardsMu.Unlock()
+	if s.guards[key] == guard {
+		delete(s.guards, key)
+	}
+}
+
+// isInvalidated tells if the key of the guard or any of the given tags was invalidated since the load started
+func (s *CacheService) isInvalidated(guard *loadGuard, tags []string) bool {
+	s.guardsMu.Lock()
+	defer s.guardsMu.Unlock()
+	if guard.invalidated {
+		return true
+	}
+	for _, tag := range tags {
+		if guard.tags[tag] {
+			return true
+		}
+	}
+	return false
+}
+
+// markInvalidated marks the loads in progress affected by the invalidation
+func (s *CacheService) markInvalidated(invalidation Invalidation) {
+	s.guardsMu.Lock()
+	defer s.guardsMu.Unlock()
+	for _, key := range invalidation.Keys {
+		if guard, ok := s.guards[key]; ok {
+			guard.invalidated = true
+		}
+	}
+	if !invalidation.All && len(invalidation.Tags) == 0 {
+		return
+	}
+	for _, guard := range s.guards {
+		if invalidation.All {
+			guard.invalidated = true
+			continue
+		}
+		for _, tag := range invalidation.Tags {
+			guard.tags[tag] = true
+		}
+	}
+}
+
+// InvalidateTags marks the loads in progress before the keys are deleted, so their values are not stored, see loadGuard
+func (s *CacheService) InvalidateTags(ctx context.Context, tags ...string) error {
+	s.markInvalidated(Invalidation{Tags: tags})
+	return s.Cache.InvalidateTags(ctx, tags...)
+}
+
+// pinger is implemented by the backends which are the remote services
+type pinger interface {
+	Ping() error
+}
+
+// Ping checks the connection to the backend, the in-process backends are always available
+func (s *CacheService) Ping() error {
+	if p, ok := s.Cache.(pinger); ok {
+		return p.Ping()
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	"github.com/go-redis/redis/v8"
+)
+
+// The in-process L1 cache sits in front of Redis. Every instance keeps its own one, so the deleted and overwritten keys are broadcast
+// through the Redis channel and each instance drops them from its L1. The messages are sent by the instances without L1 as well.
+// The L1 TTL is short, it bounds the staleness if a message is lost, and the whole L1 is purged when the subscription is (re)established
+
+type l1Invalidator struct {
+	pubsub *redis.PubSub
+	wg     sync.WaitGroup
+}
+
+// invalidationMessage lists the keys to drop from L1, the instance skips its own messages. The deleted keys and the invalidated tags
+// are reported to CacheService as well, the overwritten keys (Overwrite) are only dropped from L1
+type invalidationMessage struct {
+	Origin    string
+	Keys      []string
+	Tags      []string `json:",omitempty"`
+	Overwrite bool     `json:",omitempty"`
+}
+
+func (s *RedisCacheService) getFromL1(key string) (string, bool) {
+	if s.l1 == nil {
+		return "", false
+	}
+	return s.l1.get(key)
+}
+
+func (s *RedisCacheService) ttlFromL1(key string) (time.Duration, bool) {
+	if s.l1 == nil {
+		return 0, false
+	}
+	return s.l1.ttl(key)
+}
+
+func (s *RedisCacheService) putToL1(key, value string, ttl time.Duration) {
+	if s.l1 == nil {
+		return
+	}
+	s.l1.set(key, value, ttl)
+}
+
+// deleteFromL1 drops the keys locally and notifies the other instances
+func (s *RedisCacheService) deleteFromL1(message invalidationMessage) {
+	if len(message.Keys) == 0 {
+		return
+	}
+	if s.l1 != nil {
+		s.l1.delete(message.Keys...)
+	}
+	s.publishInvalidation(message)
+}
+
+// publishInvalidation notifies the other instances, the keys could be at their L1 even if this instance has none
+func (s *RedisCacheService) publishInvalidation(message invalidationMessage) {
+	message.Origin = s.instanceId
+	payload, err := json.Marshal(message)
+	if err != nil {
+		log.Error("Unable to marshal cache invalidation message", err.Error())
+		return
+	}
+	err = s.redisService.WithTimeoutVoid(func(cli *redis.Client, ctx context.Context, cancel context.CancelFunc) error {
+		return cli.Publish(ctx, s.invalidationChannel, string(payload)).Err()
+	})()
+	if err != nil {
+		log.Error("Unable to publish cache invalidation message", err.Error())
+	}
+}
+
+func (s *RedisCacheService) startL1Invalidator(channel string) error {
+	invalidator := &l1Invalidator{}
+	err := s.redisService.WithTimeoutVoid(func(cli *redis.Client, ctx context.Context, cancel context.CancelFunc) error {
+		// the subscription outlives the call, so it is bound to the client rather than to the call context
+		invalidator.pubsub = cli.Subscribe(context.Background(), channel)
+		_, err := invalidator.pubsub.Receive(ctx)
+		return err
+	})()
+	if err != nil {
+		if invalidator.pubsub != nil {
+			invalidator.pubsub.Close()
+		}
+		return fmt.Errorf("unable to subscribe to cache invalidation channel '%v': %w", channel, err)
+	}
+
+	messages := invalidator.pubsub.ChannelWithSubscriptions(context.Background(), 100)
+	invalidator.wg.Add(1)
+	go func() {
+		defer invalidator.wg.Done()
+		for message := range messages {
+			switch m := message.(type) {
+			case *redis.Subscription:
+				// the messages sent while the connection was down are lost
+				s.notifyInvalidation(Invalidation{All: true})
+				if s.l1 != nil {
+					s.l1.purge()
+				}
+			case *redis.Message:
+				s.handleL1Invalidation(m.Payload)
+			}
+		}
+	}()
+
+	s.invalidator = invalidator
+	return nil
+}
+
+func (s *RedisCacheService) handleL1Invalidation(payload string) {
+	var message invalidationMessage
+	err := json.Unmarshal([]byte(payload), &message)
+	if err == nil && message.Origin == s.instanceId {
+		return
+	}
+	if err != nil {
+		log.Error("Unable to unmarshal cache invalidation message", err.Error())
+		s.notifyInvalidation(Invalidation{All: true})
+		if s.l1 != nil {
+			s.l1.purge()
+		}
+		return
+	}
+	if !message.Overwrite {
+		s.notifyInvalidation(Invalidation{Keys: message.Keys, Tags: message.Tags})
+	}
+	if s.l1 != nil {
+		s.l1.delete(message.Keys...)
+	}
+}
+
+func (s *RedisCacheService) notifyInvalidation(invalidation Invalidation) {
+	if handler := s.onInvalidation.Load(); handler != nil {
+		(*handler)(invalidation)
+	}
+}
+
+func (s *RedisCacheService) stopL1Invalidator() error {
+	if s.invalidator == nil {
+		return nil
+	}
+	err := s.invalidator.pubsub.Close()
+	s.invalidator.wg.Wait()
+	return err
+}
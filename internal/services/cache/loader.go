@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/tracing"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// negativeValue marks the keys of missing entities, it is not a valid JSON so it never clashes with the real values
+const negativeValue = "\x00not_found"
+
+// LoadResult is what the loader returns on cache miss and what GetOrLoad returns to the callers
+type LoadResult struct {
+	Value string
+	// NotFound results are cached for the negative TTL
+	NotFound bool
+	// DoNotCache results are returned to the callers but never stored
+	DoNotCache bool
+	// Stale is set when the stale copy is returned because the source is unavailable
+	Stale bool
+	// Tags are registered for the stored value, see SetWithTags
+	Tags []string
+}
+
+// Loader is given the context of the load. The load coalesced from the concurrent calls is not canceled with the first caller,
+// it keeps the values of its context only, e.g. the span
+type Loader func(ctx context.Context) (LoadResult, error)
+
+// unavailableError is implemented by the loader errors meaning the source is down for a while, the stale copy is returned on them
+type unavailableError interface {
+	Unavailable() bool
+}
+
+func staleKey(key string) string {
+	return "stale_" + key
+}
+
+func IsNegativeValue(value string) bool {
+	return value == negativeValue
+}
+
+// GetOrLoad reads the key from the cache. On miss the concurrent calls for the same key are coalesced into a single load.
+// If early refresh is enabled the entry close to expiration is reloaded in the background with the probability
+// growing as the TTL runs out (XFetch), so the hot keys don't expire under the load.
+// If the loader fails because the source is unavailable the stale copy of the value is returned, see StaleTTL
+func (s *CacheService) GetOrLoad(ctx context.Context, key string, load Loader) (result LoadResult, err error) {
+	ctx, span := tracing.Start(ctx, "cache get_or_load")
+	defer func() {
+		span.SetAttributes(attribute.Bool("cache.stale", result.Stale))
+		tracing.End(span, err)
+	}()
+
+	value, err := s.Get(ctx, key)
+	if err != nil {
+		log.Error(fmt.Sprintf("Unable to read key '%v' from the cache", key), err.Error())
+	}
+
+	if err == nil && len(value) > 0 {
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		if s.shouldRefreshEarly(ctx, key) {
+			// the refresh outlives the request
+			refreshCtx := context.WithoutCancel(ctx)
+			s.loads.DoChan(key, func() (any, error) {
+				return s.loadAndStore(refreshCtx, key, load)
+			})
+		}
+		return toLoadResult(value), nil
+	}
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+
+	// the canceled caller stops waiting, the load goes on for the others and is still limited by the timeout of the source
+	loadCtx := context.WithoutCancel(ctx)
+	var loaded any
+	select {
+	case <-ctx.Done():
+		return LoadResult{}, ctx.Err()
+	case r := <-s.loads.DoChan(key, func() (any, error) {
+		return s.loadAndStore(loadCtx, key, load)
+	}):
+		loaded, err = r.Val, r.Err
+	}
+	if err != nil {
+		if stale, ok := s.getStale(ctx, key, err); ok {
+			return stale, nil
+		}
+		return LoadResult{}, err
+	}
+	result, ok := loaded.(LoadResult)
+	if !ok {
+		return LoadResult{}, fmt.Errorf("unable to convert result into LoadResult")
+	}
+	return result, nil
+}
+
+func (s *CacheService) getStale(ctx context.Context, key string, loadErr error) (LoadResult, bool) {
+	var unavailable unavailableError
+	if s.StaleTTL <= 0 || !errors.As(loadErr, &unavailable) || !unavailable.Unavailable() {
+		return LoadResult{}, false
+	}
+	value, err := s.Get(ctx, staleKey(key))
+	if err != nil {
+		log.Error(fmt.Sprintf("Unable to read stale copy of key '%v' from the cache", key), err.Error())
+		return LoadResult{}, false
+	}
+	if len(value) == 0 {
+		return LoadResult{}, false
+	}
+	log.Info(fmt.Sprintf("Stale copy of key '%v' is returned: %v", key, loadErr))
+	return LoadResult{Value: value, Stale: true}, true
+}
+
+func toLoadResult(value string) LoadResult {
+	if IsNegativeValue(value) {
+		return LoadResult{NotFound: true}
+	}
+	return LoadResult{Value: value}
+}
+
+func (s *CacheService) loadAndStore(ctx context.Context, key string, load Loader) (LoadResult, error) {
+	guard := s.startLoad(key)
+	defer s.endLoad(key, guard)
+
+	start := time.Now()
+	result, err := load(ctx)
+	if err != nil {
+		return result, err
+	}
+	s.observeLoadDuration(time.Since(start))
+
+	if result.DoNotCache || s.isInvalidated(guard, result.Tags) {
+		// the change made during the load could be missed by it, the next call loads the value again
+		return result, nil
+	}
+
+	if result.NotFound {
+		err = s.SetWithTags(ctx, key, negativeValue, s.NegativeTTL, result.Tags...)
+	} else {
+		err = s.SetWithTags(ctx, key, result.Value, s.PostsTTL, result.Tags...)
+		if err == nil && s.StaleTTL > 0 {
+			// the stale copy is registered at the same tags, so it is invalidated together with the value
+			err = s.SetWithTags(ctx, staleKey(key), result.Value, s.StaleTTL, result.Tags...)
+		}
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("Unable to put key '%v' into the cache", key), err.Error())
+	}
+
+	if s.isInvalidated(guard, result.Tags) {
+		// the invalidation came between the check and the store, it could miss the stored value
+		err = s.Cache.Delete(ctx, key, staleKey(key))
+		if err != nil {
+			log.Error(fmt.Sprintf("Unable to delete key '%v' from the cache", key), err.Error())
+		}
+	}
+
+	return result, nil
+}
+
+// shouldRefreshEarly implements XFetch: delta * beta * -ln(rand) >= ttl, where delta is the usual load duration
+func (s *CacheService) shouldRefreshEarly(ctx context.Context, key string) bool {
+	if s.EarlyRefreshBeta <= 0 {
+		return false
+	}
+	ttl, err := s.TTL(ctx, key)
+	if err != nil {
+		log.Error(fmt.Sprintf("Unable to read TTL of key '%v' from the cache", key), err.Error())
+		return false
+	}
+	if ttl <= 0 {
+		return false
+	}
+	delta := float64(s.loadDuration.Load())
+	return delta*s.EarlyRefreshBeta*-math.Log(rand.Float64()) >= float64(ttl)
+}
+
+// observeLoadDuration keeps the moving average of the load durations
+func (s *CacheService) observeLoadDuration(d time.Duration) {
+	for {
+		old := s.loadDuration.Load()
+		updated := int64(d)
+		if old > 0 {
+			updated = (old*7 + int64(d)) / 8
+		}
+		if s.loadDuration.CompareAndSwap(old, updated) {
+			return
+		}
+	}
+}
+
+// Delete removes the stale copies of the keys as well, the loads of the keys in progress are not stored, see loadGuard
+func (s *CacheService) Delete(ctx context.Context, keys ...string) error {
+	s.markInvalidated(Invalidation{Keys: keys})
+	all := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		all = append(all, key, staleKey(key))
+	}
+	return s.Cache.Delete(ctx, all...)
+}
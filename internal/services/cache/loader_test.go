@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestCacheService() *CacheService {
+	return &CacheService{
+		Cache:       CreateMemoryCacheService(),
+		PostsTTL:    time.Minute,
+		NegativeTTL: time.Minute,
+	}
+}
+
+func TestLoadAndStoreSkipsInvalidatedLoads(t *testing.T) {
+	tests := []struct {
+		name string
+		// change is made by the loader, i.e. during the load
+		change func(ctx context.Context, s *CacheService)
+		stored bool
+	}{
+		{
+			name:   "no changes",
+			change: func(ctx context.Context, s *CacheService) {},
+			stored: true,
+		},
+		{
+			name:   "key deleted",
+			change: func(ctx context.Context, s *CacheService) { s.Delete(ctx, "key") },
+			stored: false,
+		},
+		{
+			name:   "tag of the value invalidated",
+			change: func(ctx context.Context, s *CacheService) { s.InvalidateTags(ctx, "tag") },
+			stored: false,
+		},
+		{
+			name:   "key invalidated by another instance",
+			change: func(ctx context.Context, s *CacheService) { s.markInvalidated(Invalidation{Keys: []string{"key"}}) },
+			stored: false,
+		},
+		{
+			name:   "messages lost",
+			change: func(ctx context.Context, s *CacheService) { s.markInvalidated(Invalidation{All: true}) },
+			stored: false,
+		},
+		{
+			name:   "another key deleted",
+			change: func(ctx context.Context, s *CacheService) { s.Delete(ctx, "other") },
+			stored: true,
+		},
+		{
+			name:   "another tag invalidated",
+			change: func(ctx context.Context, s *CacheService) { s.InvalidateTags(ctx, "other") },
+			stored: true,
+		},
+		{
+			name:   "another key stored",
+			change: func(ctx context.Context, s *CacheService) { s.SetWithTags(ctx, "other", "value", time.Minute, "tag") },
+			stored: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestCacheService()
+
+			_, err := s.GetOrLoad(ctx, "key", func(ctx context.Context) (LoadResult, error) {
+				tt.change(ctx, s)
+				return LoadResult{Value: "value", Tags: []string{"tag"}}, nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			value, err := s.Get(ctx, "key")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stored := value == "value"; stored != tt.stored {
+				t.Fatalf("expected stored %v, got value %q", tt.stored, value)
+			}
+			if len(s.guards) != 0 {
+				t.Fatalf("expected no loads in progress, got %v", len(s.guards))
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lruCache is the bounded in-process cache, the least recently used entries are evicted when it is full.
+// The entries are kept not longer than the cache TTL, zero TTL means no limit
+type lruCache struct {
+	maxEntries int
+	maxTTL     time.Duration
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List
+	// onEvict is called with the key of the entry removed because the cache is full or the entry is expired,
+	// the lock of the cache is held, so the callback must not call it
+	onEvict func(key string)
+}
+
+type lruEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+	// sourceExpiresAt is the expiration requested by the caller, it could be later than expiresAt
+	sourceExpiresAt time.Time
+}
+
+func newLRUCache(maxEntries int, maxTTL time.Duration) *lruCache {
+	return &lruCache{
+		maxEntries: maxEntries,
+		maxTTL:     maxTTL,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (c *lruCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.lookup(key)
+	if !ok {
+		return "", false
+	}
+	return entry.value, true
+}
+
+// ttl returns the remaining time until the requested expiration, false if the entry is missing or has no expiration
+func (c *lruCache) ttl(key string) (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.lookup(key)
+	if !ok || entry.sourceExpiresAt.IsZero() {
+		return 0, false
+	}
+	return time.Until(entry.sourceExpiresAt), true
+}
+
+// set puts the value for the given TTL but not longer than the cache TTL, zero means no expiration
+func (c *lruCache) set(key, value string, ttl time.Duration) {
+	now := time.Now()
+	var expiresAt, sourceExpiresAt time.Time
+	if ttl > 0 {
+		sourceExpiresAt = now.Add(ttl)
+	}
+	switch {
+	case c.maxTTL > 0 && (ttl <= 0 || ttl > c.maxTTL):
+		expiresAt = now.Add(c.maxTTL)
+	case ttl > 0:
+		expiresAt = sourceExpiresAt
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		entry.sourceExpiresAt = sourceExpiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt, sourceExpiresAt: sourceExpiresAt})
+	for c.order.Len() > c.maxEntries {
+		c.evictElement(c.order.Back())
+	}
+}
+
+func (c *lruCache) delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.removeElement(element)
+		}
+	}
+}
+
+func (c *lruCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// lookup returns the live entry and marks it as recently used, the caller holds the lock
+func (c *lruCache) lookup(key string) (*lruEntry, bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.evictElement(element)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry, true
+}
+
+func (c *lruCache) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry).key)
+}
+
+func (c *lruCache) evictElement(element *list.Element) {
+	c.removeElement(element)
+	if c.onEvict != nil {
+		c.onEvict(element.Value.(*lruEntry).key)
+	}
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
+)
+
+// MemoryCacheService keeps the entries in the process memory, it is meant for local runs with a single instance.
+// All operations are made under the lock, so the evicted entries are removed from their tags consistently
+type MemoryCacheService struct {
+	entries *lruCache
+	mu      sync.Mutex
+	tags    map[string]map[string]struct{}
+	// keyTags are the tags of every key, so the tags of the evicted key are pruned
+	keyTags map[string]map[string]struct{}
+}
+
+func CreateMemoryCacheService() *MemoryCacheService {
+	maxEntries, err := strconv.Atoi(utils.EnvVarDefault("CACHE_MEMORY_MAX_ENTRIES", "100000"))
+	if err != nil || maxEntries <= 0 {
+		maxEntries = 100000
+	}
+	result := &MemoryCacheService{
+		entries: newLRUCache(maxEntries, 0),
+		tags:    make(map[string]map[string]struct{}),
+		keyTags: make(map[string]map[string]struct{}),
+	}
+	// the eviction happens within the calls below, which hold the lock already
+	result.entries.onEvict = result.untag
+	return result
+}
+
+func (s *MemoryCacheService) Shutdown() error {
+	return nil
+}
+
+func (s *MemoryCacheService) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, _ := s.entries.get(key)
+	return value, nil
+}
+
+func (s *MemoryCacheService) TTL(ctx context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ttl, _ := s.entries.ttl(key)
+	return ttl, nil
+}
+
+func (s *MemoryCacheService) Set(ctx context.Context, key, value string, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries.set(key, value, expiration)
+	return nil
+}
+
+func (s *MemoryCacheService) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries.delete(keys...)
+	for _, key := range keys {
+		s.untag(key)
+	}
+	return nil
+}
+
+func (s *MemoryCacheService) SetWithTags(ctx context.Context, key, value string, expiration time.Duration, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries.set(key, value, expiration)
+	if len(tags) == 0 {
+		return nil
+	}
+	keyTags, ok := s.keyTags[key]
+	if !ok {
+		keyTags = make(map[string]struct{})
+		s.keyTags[key] = keyTags
+	}
+	for _, tag := range tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			s.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+		keyTags[tag] = struct{}{}
+	}
+	return nil
+}
+
+func (s *MemoryCacheService) InvalidateTags(ctx context.Context, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			s.entries.delete(key)
+			s.untag(key)
+		}
+		delete(s.tags, tag)
+	}
+	return nil
+}
+
+// untag removes the key from all its tags and drops the tags left empty, the caller holds the lock
+func (s *MemoryCacheService) untag(key string) {
+	for tag := range s.keyTags[key] {
+		keys := s.tags[tag]
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(s.tags, tag)
+		}
+	}
+	delete(s.keyTags, key)
+}
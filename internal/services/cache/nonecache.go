@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// NoneCacheService stores nothing, every read is a miss
+type NoneCacheService struct{}
+
+func CreateNoneCacheService() *NoneCacheService {
+	return &NoneCacheService{}
+}
+
+func (s *NoneCacheService) Shutdown() error {
+	return nil
+}
+
+func (s *NoneCacheService) Get(ctx context.Context, key string) (string, error) {
+	return "", nil
+}
+
+func (s *NoneCacheService) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return 0, nil
+}
+
+func (s *NoneCacheService) Set(ctx context.Context, key, value string, expiration time.Duration) error {
+	return nil
+}
+
+func (s *NoneCacheService) Delete(ctx context.Context, keys ...string) error {
+	return nil
+}
+
+func (s *NoneCacheService) SetWithTags(ctx context.Context, key, value string, expiration time.Duration, tags ...string) error {
+	return nil
+}
+
+func (s *NoneCacheService) InvalidateTags(ctx context.Context, tags ...string) error {
+	return nil
+}
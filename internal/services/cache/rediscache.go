@@ -4,29 +4,89 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"sync/atomic"
 	"time"
 
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/metrics"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/tracing"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
 	redisService "github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/redis"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
 	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const (
+	CACHE_LAYER_L1    = "l1"
+	CACHE_LAYER_REDIS = "redis"
+)
+
+// extendTTLScript sets the TTL of the key unless the key already lives longer
+var extendTTLScript = redis.NewScript(`
+if redis.call('TTL', KEYS[1]) < tonumber(ARGV[1]) then
+	return redis.call('EXPIRE', KEYS[1], ARGV[1])
+end
+return 0`)
+
 type RedisCacheService struct {
 	redisService *redisService.RedisService
-	PostsTTL     time.Duration
+	l1           *lruCache
+	invalidator  *l1Invalidator
+	// instanceId tells the own invalidation messages from the ones of the other instances
+	instanceId          string
+	invalidationChannel string
+	// onInvalidation is given the invalidations of the other instances, see CacheService.markInvalidated
+	onInvalidation atomic.Pointer[func(Invalidation)]
 }
 
 func CreateRedisCacheService() *RedisCacheService {
-	postsTTL := utils.EnvVarDurationDefault("CACHE_POSTS_TTL_IN_MINUTES", time.Minute, 10*time.Minute)
-	return &RedisCacheService{
-		redisService: redisService.CreateRedisService(),
-		PostsTTL:     postsTTL,
+	l1MaxEntries, err := strconv.Atoi(utils.EnvVarDefault("CACHE_L1_MAX_ENTRIES", "10000"))
+	if err != nil || l1MaxEntries < 0 {
+		l1MaxEntries = 10000
+	}
+	l1TTL := utils.EnvVarDurationDefault("CACHE_L1_TTL_IN_SECONDS", time.Second, 30*time.Second)
+
+	result := &RedisCacheService{
+		redisService:        redisService.CreateRedisService(),
+		instanceId:          uuid.NewString(),
+		invalidationChannel: utils.EnvVarDefault("CACHE_INVALIDATION_CHANNEL", "posts_cache_invalidation"),
+	}
+
+	if l1MaxEntries > 0 {
+		result.l1 = newLRUCache(l1MaxEntries, l1TTL)
 	}
+	// the invalidation messages are received without L1 as well, they keep the loads in progress from being stored
+	err = result.startL1Invalidator(result.invalidationChannel)
+	if err != nil {
+		// without invalidation messages the L1 would serve stale entries, so it is better to go without it
+		log.Error("Unable to start L1 cache invalidation, L1 cache is disabled", err.Error())
+		result.l1 = nil
+	}
+
+	return result
+}
+
+// OnInvalidation sets the handler of the deletes and the tags invalidations made by the other instances
+func (s *RedisCacheService) OnInvalidation(handler func(Invalidation)) {
+	s.onInvalidation.Store(&handler)
+}
+
+func (s *RedisCacheService) Ping() error {
+	return s.redisService.WithTimeoutVoid(func(cli *redis.Client, ctx context.Context, cancel context.CancelFunc) error {
+		return cli.Ping(ctx).Err()
+	})()
 }
 
 func (s *RedisCacheService) Shutdown() error {
 	result := []error{}
-	err := s.redisService.Shutdown()
+	err := s.stopL1Invalidator()
+	if err != nil {
+		result = append(result, err)
+	}
+	err = s.redisService.Shutdown()
 	if err != nil {
 		result = append(result, err)
 	}
@@ -36,32 +96,167 @@ func (s *RedisCacheService) Shutdown() error {
 	return nil
 }
 
-func (s *RedisCacheService) Get(key string) (string, error) {
-	data, err := s.redisService.WithTimeout(func(cli *redis.Client, ctx context.Context, cancel context.CancelFunc) (any, error) {
-		return cli.Get(ctx, key).Result()
+// startSpan starts the span of the round trip to Redis, the L1 hits are not traced
+func startSpan(ctx context.Context, operation string) trace.Span {
+	_, span := tracing.Start(ctx, "redis "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperationKey.String(operation)),
+	)
+	return span
+}
+
+func (s *RedisCacheService) Get(ctx context.Context, key string) (string, error) {
+	if value, ok := s.getFromL1(key); ok {
+		metrics.CountCacheRequest(CACHE_LAYER_L1, metrics.CACHE_RESULT_HIT)
+		return value, nil
+	}
+	if s.l1 != nil {
+		metrics.CountCacheRequest(CACHE_LAYER_L1, metrics.CACHE_RESULT_MISS)
+	}
+
+	var get *redis.StringCmd
+	var pttl *redis.DurationCmd
+	start := time.Now()
+	span := startSpan(ctx, "get")
+	err := s.redisService.WithTimeoutVoid(func(cli *redis.Client, ctx context.Context, cancel context.CancelFunc) error {
+		// TTL is read in the same round trip, so the L1 knows when the entry expires at Redis
+		_, err := cli.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			get = pipe.Get(ctx, key)
+			pttl = pipe.PTTL(ctx, key)
+			return nil
+		})
+		return err
 	})()
+	metrics.ObserveRedisCommand("get", start)
 
 	if err != nil && errors.Is(err, redis.Nil) {
+		metrics.CountCacheRequest(CACHE_LAYER_REDIS, metrics.CACHE_RESULT_MISS)
+		tracing.End(span, nil)
 		return "", nil
 	}
 
+	tracing.End(span, err)
 	if err != nil {
+		metrics.CountCacheRequest(CACHE_LAYER_REDIS, metrics.CACHE_RESULT_ERROR)
 		return "", err
 	}
 
-	result, ok := data.(string)
+	metrics.CountCacheRequest(CACHE_LAYER_REDIS, metrics.CACHE_RESULT_HIT)
+	result := get.Val()
+	s.putToL1(key, result, pttl.Val())
+	return result, nil
+}
+
+func (s *RedisCacheService) TTL(ctx context.Context, key string) (time.Duration, error) {
+	if ttl, ok := s.ttlFromL1(key); ok {
+		return ttl, nil
+	}
+	defer metrics.ObserveRedisCommand("ttl", time.Now())
+	span := startSpan(ctx, "ttl")
+
+	data, err := s.redisService.WithTimeout(func(cli *redis.Client, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		return cli.PTTL(ctx, key).Result()
+	})()
+	tracing.End(span, err)
+
+	if err != nil {
+		return 0, err
+	}
+
+	result, ok := data.(time.Duration)
 	if !ok {
-		return "", fmt.Errorf("unable cast to string")
+		return 0, fmt.Errorf("unable cast to duration")
+	}
+	// negative values mean that the key is missing or has no expiration
+	if result < 0 {
+		return 0, nil
 	}
-	return result, err
+	return result, nil
 }
 
-func (s *RedisCacheService) Set(key, value string, expiration time.Duration) error {
-	return s.redisService.WithTimeoutVoid(func(cli *redis.Client, ctx context.Context, cancel context.CancelFunc) error {
+func (s *RedisCacheService) Set(ctx context.Context, key, value string, expiration time.Duration) error {
+	defer metrics.ObserveRedisCommand("set", time.Now())
+	span := startSpan(ctx, "set")
+	err := s.redisService.WithTimeoutVoid(func(cli *redis.Client, ctx context.Context, cancel context.CancelFunc) error {
 		err := cli.Set(ctx, key, value, expiration).Err()
 		if err != nil {
 			return err
 		}
 		return err
 	})()
+	tracing.End(span, err)
+	if err == nil {
+		// the other instances could keep the overwritten value at their L1
+		s.putToL1(key, value, expiration)
+		s.publishInvalidation(invalidationMessage{Keys: []string{key}, Overwrite: true})
+	}
+	return err
+}
+
+func (s *RedisCacheService) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	defer metrics.ObserveRedisCommand("delete", time.Now())
+	span := startSpan(ctx, "delete")
+	err := s.redisService.WithTimeoutVoid(func(cli *redis.Client, ctx context.Context, cancel context.CancelFunc) error {
+		return cli.Del(ctx, keys...).Err()
+	})()
+	tracing.End(span, err)
+	s.deleteFromL1(invalidationMessage{Keys: keys})
+	return err
+}
+
+// SetWithTags puts the value and registers its key at the given tags, so the key could be deleted later by any of them
+func (s *RedisCacheService) SetWithTags(ctx context.Context, key, value string, expiration time.Duration, tags ...string) error {
+	defer metrics.ObserveRedisCommand("set_with_tags", time.Now())
+	span := startSpan(ctx, "set_with_tags")
+	err := s.redisService.WithTimeoutVoid(func(cli *redis.Client, ctx context.Context, cancel context.CancelFunc) error {
+		_, err := cli.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, value, expiration)
+			for _, tag := range tags {
+				pipe.SAdd(ctx, tagKey(tag), key)
+				// the tag should live at least as long as the keys registered at it, so its TTL is never shortened
+				extendTTLScript.Eval(ctx, pipe, []string{tagKey(tag)}, int64(expiration/time.Second))
+			}
+			return nil
+		})
+		return err
+	})()
+	tracing.End(span, err)
+	if err == nil {
+		// the other instances could keep the overwritten value at their L1
+		s.putToL1(key, value, expiration)
+		s.publishInvalidation(invalidationMessage{Keys: []string{key}, Overwrite: true})
+	}
+	return err
+}
+
+// InvalidateTags deletes all keys registered at the given tags and the tags themselves
+func (s *RedisCacheService) InvalidateTags(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	defer metrics.ObserveRedisCommand("invalidate_tags", time.Now())
+	span := startSpan(ctx, "invalidate_tags")
+	keys := make([]string, 0, len(tags))
+	err := s.redisService.WithTimeoutVoid(func(cli *redis.Client, ctx context.Context, cancel context.CancelFunc) error {
+		for _, tag := range tags {
+			members, err := cli.SMembers(ctx, tagKey(tag)).Result()
+			if err != nil && !errors.Is(err, redis.Nil) {
+				return fmt.Errorf("unable to get keys of tag '%v': %w", tag, err)
+			}
+			keys = append(keys, members...)
+			keys = append(keys, tagKey(tag))
+		}
+		return cli.Del(ctx, keys...).Err()
+	})()
+	tracing.End(span, err)
+	// the tags are sent as well, the loads in progress at the other instances could get the values with them
+	s.deleteFromL1(invalidationMessage{Keys: keys, Tags: tags})
+	return err
+}
+
+func tagKey(tag string) string {
+	return fmt.Sprintf("cache_tag_%v", tag)
 }
@@ -11,6 +11,20 @@ type Comment struct {
 	State           string
 	CreateDate      time.Time
 	LastUpdateDate  time.Time
+	// EditCount is the number of the changes of the text, EditedAt is the date of the last one
+	EditCount int
+	EditedAt  *time.Time
+}
+
+// CommentEdit is the text of the comment before the edit with the given number, it is replaced by the editor at CreateDate
+type CommentEdit struct {
+	Id         int
+	CommentId  int
+	PostUuid   string
+	Edit       int
+	Text       string
+	EditorUuid string
+	CreateDate time.Time
 }
 
 type CommentForQueue struct {
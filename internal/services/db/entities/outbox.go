@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+type OutboxMessage struct {
+	Id      int
+	Topic   string
+	Payload string
+	// TraceContext is the JSON of the trace headers of the request which made the change, empty if there was none
+	TraceContext    string
+	Attempts        int
+	NextAttemptDate time.Time
+	CreateDate      time.Time
+}
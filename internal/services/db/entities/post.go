@@ -34,3 +34,26 @@ type PostWithTagsForQueue struct {
 	State      string
 	TagIds     []int
 }
+
+// PostStateTransition is the record of the state change, ActorUuid is empty for the changes made by the backend services
+type PostStateTransition struct {
+	Id         int
+	PostUuid   string
+	FromState  string
+	ToState    string
+	ActorUuid  string
+	CreateDate time.Time
+}
+
+// PostRevision is the content of the post after the change, EditorUuid is empty for the content written before the revisions
+// and for the changes made by the backend services
+type PostRevision struct {
+	Id          int
+	PostUuid    string
+	Revision    int
+	Text        string
+	PreviewText string
+	Topic       string
+	EditorUuid  string
+	CreateDate  time.Time
+}
@@ -18,56 +18,146 @@ type CreateCommentParams struct {
 	LinkedCommentId interface{}
 }
 
+type GetCommentsThreadsParams struct {
+	PostUuid        interface{}
+	AfterCreateDate interface{}
+	AfterId         interface{}
+	Limit           int
+	Depth           int
+}
+
 type UpdateCommentParams struct {
 	Id              interface{}
 	AuthorUuid      interface{}
-	PostId          interface{}
+	PostUuid        interface{}
 	Text            interface{}
 	LinkedCommentId interface{}
 	State           interface{}
 }
 
-// TODO: add memory safe pagination without direct offset, use sorting by id and where criteria
+type GetCommentsParams struct {
+	PostUuid        interface{}
+	AfterCreateDate interface{}
+	AfterId         interface{}
+	Limit           int
+}
 
 const (
 	GET_COMMENTS_QUERY = `SELECT 
-		id, author_uuid, text, linked_comment_id, state, create_date, last_update_date 
+		id, author_uuid, post_uuid, text, linked_comment_id, state, create_date, last_update_date, edit_count, edited_at 
 	FROM comments 
-	WHERE state != $4 and post_uuid = $1
-	LIMIT $2 OFFSET $3`
+	WHERE post_uuid = $1 AND state != $2 AND ($3::timestamp IS NULL OR (create_date, id) > ($3::timestamp, $4::bigint))
+	ORDER BY create_date ASC, id ASC
+	LIMIT $5`
+
+	GET_COMMENTS_THREADS_QUERY = `WITH RECURSIVE roots AS (
+		SELECT id, author_uuid, post_uuid, text, linked_comment_id, state, create_date, last_update_date, edit_count, edited_at
+		FROM comments
+		WHERE post_uuid = $1 AND linked_comment_id IS NULL AND ($2::timestamp IS NULL OR (create_date, id) > ($2::timestamp, $3::bigint))
+		ORDER BY create_date ASC, id ASC
+		LIMIT $4
+	), threads AS (
+		SELECT id, author_uuid, post_uuid, text, linked_comment_id, state, create_date, last_update_date, edit_count, edited_at, 1 AS depth
+		FROM roots
+		UNION ALL
+		SELECT comments.id, comments.author_uuid, comments.post_uuid, comments.text, comments.linked_comment_id, comments.state, comments.create_date, comments.last_update_date, comments.edit_count, comments.edited_at, threads.depth + 1
+		FROM comments
+		INNER JOIN threads ON comments.linked_comment_id = threads.id
+		WHERE comments.post_uuid = $1 AND threads.depth < $5
+	)
+	SELECT 
+		id, author_uuid, post_uuid, text, linked_comment_id, state, create_date, last_update_date, edit_count, edited_at 
+	FROM threads 
+	ORDER BY create_date ASC, id ASC`
 
 	GET_COMMENT_QUERY = `SELECT 
-		id, author_uuid, post_uuid, text, linked_comment_id, state, create_date, last_update_date 
+		id, author_uuid, post_uuid, text, linked_comment_id, state, create_date, last_update_date, edit_count, edited_at 
+	FROM comments 
+	WHERE id = $1 and post_uuid = $2 and state != $3`
+
+	LOCK_COMMENT_QUERY = `SELECT 
+		id, author_uuid, post_uuid, text, linked_comment_id, state, create_date, last_update_date, edit_count, edited_at 
 	FROM comments 
-	WHERE id = $1 and state != $2`
+	WHERE id = $1 and post_uuid = $2 and state != $3
+	FOR UPDATE`
 
 	CREATE_COMMENT_QUERY = `INSERT INTO comments
 		(author_uuid, post_uuid, text, linked_comment_id, state, create_date, last_update_date) 
 		VALUES($1, $2, $3, $4, $5, $6, $7) 
 	RETURNING id`
 
+	// the change of the text is counted as the edit, the same text is not
 	UPDATE_COMMENT_QUERY = `UPDATE comments
 	SET text = COALESCE($2, text),
 		state = COALESCE($3, state),
-		last_update_date = $4
-	WHERE id = $1 and state != $5`
+		last_update_date = $4,
+		edit_count = CASE WHEN $2::text IS NOT NULL AND $2::text != text THEN edit_count + 1 ELSE edit_count END,
+		edited_at = CASE WHEN $2::text IS NOT NULL AND $2::text != text THEN $4 ELSE edited_at END
+	WHERE id = $1 and post_uuid = $6 and state != $5`
 
 	DELETE_COMMENT_QUERY = `UPDATE comments 
-	SET state = $2 
-	WHERE id = $1 and state != $2`
+	SET state = $3 
+	WHERE id = $1 and post_uuid = $2 and state != $3`
 )
 
-func GetComments(tx *sql.Tx, ctx context.Context, postUuid string, limit int, offset int) ([]entities.Comment, error) {
+func GetComments(tx *sql.Tx, ctx context.Context, params *GetCommentsParams) ([]entities.Comment, error) {
+	var comments []entities.Comment
+
+	rows, err := tx.QueryContext(ctx, GET_COMMENTS_QUERY, params.PostUuid, utilsEntities.COMMENT_STATE_DELETED, params.AfterCreateDate, params.AfterId, params.Limit)
+	if err != nil {
+		return comments, fmt.Errorf("error at loading comments by post uuid '%v', case after Query: %w", params.PostUuid, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var comment entities.Comment
+		err := rows.Scan(&comment.Id, &comment.AuthorUuid, &comment.PostUuid, &comment.Text, &comment.LinkedCommentId, &comment.State, &comment.CreateDate, &comment.LastUpdateDate, &comment.EditCount, &comment.EditedAt)
+		if err != nil {
+			return comments, fmt.Errorf("error at loading comments by post uuid '%v', case iterating and using rows.Scan: %w", params.PostUuid, err)
+		}
+		comments = append(comments, comment)
+	}
+	err = rows.Err()
+	if err != nil {
+		return comments, fmt.Errorf("error at loading comments by post uuid '%v', case after iterating: %w", params.PostUuid, err)
+	}
+
+	return comments, nil
+}
+
+// GetCommentsThreads returns a page of root comments of the post with all their replies up to the given depth (roots have depth 1).
+// Deleted comments are returned as well, so the caller is able to keep threads connected
+func GetCommentsThreads(tx *sql.Tx, ctx context.Context, params *GetCommentsThreadsParams) ([]entities.Comment, error) {
 	var comments []entities.Comment
-	// TODO: implement getting comments by post UUID with safe pagination (where id > 0 + limit)
+
+	rows, err := tx.QueryContext(ctx, GET_COMMENTS_THREADS_QUERY, params.PostUuid, params.AfterCreateDate, params.AfterId, params.Limit, params.Depth)
+	if err != nil {
+		return comments, fmt.Errorf("error at loading comments threads by post uuid '%v', case after Query: %w", params.PostUuid, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var comment entities.Comment
+		err := rows.Scan(&comment.Id, &comment.AuthorUuid, &comment.PostUuid, &comment.Text, &comment.LinkedCommentId, &comment.State, &comment.CreateDate, &comment.LastUpdateDate, &comment.EditCount, &comment.EditedAt)
+		if err != nil {
+			return comments, fmt.Errorf("error at loading comments threads by post uuid '%v', case iterating and using rows.Scan: %w", params.PostUuid, err)
+		}
+		comments = append(comments, comment)
+	}
+	err = rows.Err()
+	if err != nil {
+		return comments, fmt.Errorf("error at loading comments threads by post uuid '%v', case after iterating: %w", params.PostUuid, err)
+	}
+
 	return comments, nil
 }
 
-func GetComment(tx *sql.Tx, ctx context.Context, id int) (entities.Comment, error) {
+// GetComment loads the comment of the post, the ids are unique within the shard only and the comment of another post is not found
+func GetComment(tx *sql.Tx, ctx context.Context, postUuid string, id int) (entities.Comment, error) {
 	var comment entities.Comment
 
-	err := tx.QueryRowContext(ctx, GET_COMMENT_QUERY, id, utilsEntities.COMMENT_STATE_DELETED).
-		Scan(&comment.Id, &comment.AuthorUuid, &comment.PostUuid, &comment.Text, &comment.LinkedCommentId, &comment.State, &comment.CreateDate, &comment.LastUpdateDate)
+	err := tx.QueryRowContext(ctx, GET_COMMENT_QUERY, id, postUuid, utilsEntities.COMMENT_STATE_DELETED).
+		Scan(&comment.Id, &comment.AuthorUuid, &comment.PostUuid, &comment.Text, &comment.LinkedCommentId, &comment.State, &comment.CreateDate, &comment.LastUpdateDate, &comment.EditCount, &comment.EditedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return comment, err
 	} else if err != nil {
@@ -77,6 +167,21 @@ func GetComment(tx *sql.Tx, ctx context.Context, id int) (entities.Comment, erro
 	return comment, nil
 }
 
+// LockComment loads the comment and locks it till the end of the transaction
+func LockComment(tx *sql.Tx, ctx context.Context, postUuid string, id int) (entities.Comment, error) {
+	var comment entities.Comment
+
+	err := tx.QueryRowContext(ctx, LOCK_COMMENT_QUERY, id, postUuid, utilsEntities.COMMENT_STATE_DELETED).
+		Scan(&comment.Id, &comment.AuthorUuid, &comment.PostUuid, &comment.Text, &comment.LinkedCommentId, &comment.State, &comment.CreateDate, &comment.LastUpdateDate, &comment.EditCount, &comment.EditedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return comment, err
+	} else if err != nil {
+		return comment, fmt.Errorf("error at locking comment by id '%v', case after QueryRow.Scan: %w", id, err)
+	}
+
+	return comment, nil
+}
+
 func CreateComment(tx *sql.Tx, ctx context.Context, params *CreateCommentParams) (int, error) {
 	lastInsertId := -1
 
@@ -101,14 +206,14 @@ func UpdateComment(tx *sql.Tx, ctx context.Context, params *UpdateCommentParams)
 		return fmt.Errorf("error at updating comment, case after preparing statement: %w", err)
 	}
 	defer stmt.Close()
-	res, err := stmt.ExecContext(ctx, params.Id, params.Text, params.State, lastUpdateDate, utilsEntities.COMMENT_STATE_DELETED)
+	res, err := stmt.ExecContext(ctx, params.Id, params.Text, params.State, lastUpdateDate, utilsEntities.COMMENT_STATE_DELETED, params.PostUuid)
 	if err != nil {
-		return fmt.Errorf("error at updating comment (Id: %v, AuthorUuid: '%v', PostId: '%v'), case after executing statement: %w", params.Id, params.AuthorUuid, params.PostId, err)
+		return fmt.Errorf("error at updating comment (Id: %v, AuthorUuid: '%v', PostUuid: '%v'), case after executing statement: %w", params.Id, params.AuthorUuid, params.PostUuid, err)
 	}
 
 	affectedRowsCount, err := res.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("error at updating comment (Id: %v, AuthorUuid: '%v', PostId: '%v'), case after counting affected rows: %w", params.Id, params.AuthorUuid, params.PostId, err)
+		return fmt.Errorf("error at updating comment (Id: %v, AuthorUuid: '%v', PostUuid: '%v'), case after counting affected rows: %w", params.Id, params.AuthorUuid, params.PostUuid, err)
 	}
 	if affectedRowsCount == 0 {
 		return sql.ErrNoRows
@@ -117,13 +222,13 @@ func UpdateComment(tx *sql.Tx, ctx context.Context, params *UpdateCommentParams)
 	return nil
 }
 
-func DeleteComment(tx *sql.Tx, ctx context.Context, id int) error {
+func DeleteComment(tx *sql.Tx, ctx context.Context, postUuid string, id int) error {
 	stmt, err := tx.PrepareContext(ctx, DELETE_COMMENT_QUERY)
 	if err != nil {
 		return fmt.Errorf("error at deleting comment, case after preparing statement: %w", err)
 	}
 	defer stmt.Close()
-	res, err := stmt.ExecContext(ctx, id, utilsEntities.COMMENT_STATE_DELETED)
+	res, err := stmt.ExecContext(ctx, id, postUuid, utilsEntities.COMMENT_STATE_DELETED)
 	if err != nil {
 		return fmt.Errorf("error at deleting comment by id '%v', case after executing statement: %w", id, err)
 	}
@@ -0,0 +1,100 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+)
+
+type CreateCommentEditParams struct {
+	CommentId  int
+	PostUuid   string
+	Edit       int
+	Text       string
+	EditorUuid string
+	CreateDate time.Time
+}
+
+const (
+	CREATE_COMMENT_EDIT_QUERY = `INSERT INTO comment_edits
+		(comment_id, post_uuid, edit, text, editor_uuid, create_date)
+		VALUES($1, $2, $3, $4, $5, $6)`
+
+	GET_COMMENT_EDITS_QUERY = `SELECT
+		id, comment_id, post_uuid, edit, text, COALESCE(editor_uuid::text, ''), create_date
+	FROM comment_edits
+	WHERE comment_id = $1 AND post_uuid = $2
+	ORDER BY edit`
+
+	GET_ALL_COMMENT_EDITS_BY_POST_UUID_QUERY = `SELECT
+		id, comment_id, post_uuid, edit, text, COALESCE(editor_uuid::text, ''), create_date
+	FROM comment_edits
+	WHERE post_uuid = $1
+	ORDER BY comment_id, edit`
+
+	// the edits are copied by cmd/reshard, the ones copied on the previous passes are skipped
+	COPY_COMMENT_EDIT_QUERY = `INSERT INTO comment_edits
+		(comment_id, post_uuid, edit, text, editor_uuid, create_date)
+		VALUES($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (comment_id, edit) DO NOTHING`
+
+	DELETE_ALL_COMMENT_EDITS_BY_POST_UUID_QUERY = `DELETE FROM comment_edits WHERE post_uuid = $1`
+)
+
+func CreateCommentEdit(tx *sql.Tx, ctx context.Context, params *CreateCommentEditParams) error {
+	_, err := tx.ExecContext(ctx, CREATE_COMMENT_EDIT_QUERY,
+		params.CommentId, params.PostUuid, params.Edit, params.Text, toNullUuid(params.EditorUuid), params.CreateDate)
+	if err != nil {
+		return fmt.Errorf("error at inserting edit %v of comment %v, case after executing statement: %w", params.Edit, params.CommentId, err)
+	}
+	return nil
+}
+
+// GetCommentEdits returns the previous texts of the comment from the first edit to the last one
+func GetCommentEdits(tx *sql.Tx, ctx context.Context, postUuid string, commentId int) ([]entities.CommentEdit, error) {
+	return getCommentEdits(tx, ctx, fmt.Sprintf("comment %v", commentId), GET_COMMENT_EDITS_QUERY, commentId, postUuid)
+}
+
+// GetAllCommentEditsByPostUuid returns the edits of all comments of the post, it is used by cmd/reshard
+func GetAllCommentEditsByPostUuid(tx *sql.Tx, ctx context.Context, postUuid string) ([]entities.CommentEdit, error) {
+	return getCommentEdits(tx, ctx, fmt.Sprintf("comments of post '%v'", postUuid), GET_ALL_COMMENT_EDITS_BY_POST_UUID_QUERY, postUuid)
+}
+
+func getCommentEdits(tx *sql.Tx, ctx context.Context, of string, query string, args ...any) ([]entities.CommentEdit, error) {
+	var result []entities.CommentEdit
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return result, fmt.Errorf("error at loading edits of %v, case after Query: %w", of, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e entities.CommentEdit
+		err := rows.Scan(&e.Id, &e.CommentId, &e.PostUuid, &e.Edit, &e.Text, &e.EditorUuid, &e.CreateDate)
+		if err != nil {
+			return result, fmt.Errorf("error at loading edits of %v, case iterating and using rows.Scan: %w", of, err)
+		}
+		result = append(result, e)
+	}
+	err = rows.Err()
+	if err != nil {
+		return result, fmt.Errorf("error at loading edits of %v, case after iterating: %w", of, err)
+	}
+
+	return result, nil
+}
+
+// CopyCommentEdits writes the edits of the comments of the moved post into the target shard
+func CopyCommentEdits(tx *sql.Tx, ctx context.Context, edits []entities.CommentEdit) error {
+	for _, e := range edits {
+		_, err := tx.ExecContext(ctx, COPY_COMMENT_EDIT_QUERY, e.CommentId, e.PostUuid, e.Edit, e.Text, toNullUuid(e.EditorUuid), e.CreateDate)
+		if err != nil {
+			return fmt.Errorf("error at copying edit %v of comment %v, case after executing statement: %w", e.Edit, e.CommentId, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const (
+	PING_QUERY = `SELECT 1`
+)
+
+func Ping(tx *sql.Tx, ctx context.Context) error {
+	var result int
+	err := tx.QueryRowContext(ctx, PING_QUERY).Scan(&result)
+	if err != nil {
+		return fmt.Errorf("error at ping, case after QueryRow.Scan: %w", err)
+	}
+	return nil
+}
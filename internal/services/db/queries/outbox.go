@@ -0,0 +1,123 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+)
+
+const (
+	CREATE_OUTBOX_MESSAGE_QUERY = `INSERT INTO outbox
+		(topic, payload, trace_context, attempts, next_attempt_date, create_date) 
+		VALUES($1, $2, $3, 0, $4, $4) 
+	RETURNING id`
+
+	// the messages are claimed by moving next_attempt_date to the end of the lease, so several app instances could relay the same shard
+	// without sending duplicates while the messages are published outside of the transaction
+	CLAIM_UNPUBLISHED_OUTBOX_MESSAGES_QUERY = `UPDATE outbox 
+	SET next_attempt_date = $3 
+	WHERE id IN (
+		SELECT id FROM outbox 
+		WHERE publish_date IS NULL AND next_attempt_date <= $1
+		ORDER BY id ASC
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING id, topic, payload, COALESCE(trace_context, ''), attempts, next_attempt_date, create_date`
+
+	MARK_OUTBOX_MESSAGE_PUBLISHED_QUERY = `UPDATE outbox 
+	SET publish_date = $2,
+		attempts = attempts + 1,
+		last_error = NULL 
+	WHERE id = $1`
+
+	MARK_OUTBOX_MESSAGE_FAILED_QUERY = `UPDATE outbox 
+	SET attempts = attempts + 1,
+		last_error = $2,
+		next_attempt_date = $3 
+	WHERE id = $1`
+
+	// the messages are deleted in batches, so the pruning of a long backlog does not hold the locks for long
+	DELETE_PUBLISHED_OUTBOX_MESSAGES_QUERY = `DELETE FROM outbox 
+	WHERE id IN (
+		SELECT id FROM outbox 
+		WHERE publish_date IS NOT NULL AND publish_date < $1 
+		ORDER BY publish_date ASC 
+		LIMIT $2
+	)`
+)
+
+// CreateOutboxMessage stores the message, the empty trace context is stored as NULL
+func CreateOutboxMessage(tx *sql.Tx, ctx context.Context, topic string, payload string, traceContext string) (int, error) {
+	lastInsertId := -1
+
+	err := tx.QueryRowContext(ctx, CREATE_OUTBOX_MESSAGE_QUERY, topic, payload, sql.NullString{String: traceContext, Valid: traceContext != ""}, time.Now()).
+		Scan(&lastInsertId) // scan will release the connection
+	if err != nil {
+		return -1, fmt.Errorf("error at inserting outbox message (Topic: '%v') into db, case after QueryRow.Scan: %w", topic, err)
+	}
+
+	return lastInsertId, nil
+}
+
+// ClaimUnpublishedOutboxMessages returns up to limit due messages ordered by id, they are not returned again until leaseUntil
+func ClaimUnpublishedOutboxMessages(tx *sql.Tx, ctx context.Context, limit int, leaseUntil time.Time) ([]entities.OutboxMessage, error) {
+	var result []entities.OutboxMessage
+
+	rows, err := tx.QueryContext(ctx, CLAIM_UNPUBLISHED_OUTBOX_MESSAGES_QUERY, time.Now(), limit, leaseUntil)
+	if err != nil {
+		return result, fmt.Errorf("error at loading outbox messages, case after Query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var message entities.OutboxMessage
+		err := rows.Scan(&message.Id, &message.Topic, &message.Payload, &message.TraceContext, &message.Attempts, &message.NextAttemptDate, &message.CreateDate)
+		if err != nil {
+			return result, fmt.Errorf("error at loading outbox messages, case iterating and using rows.Scan: %w", err)
+		}
+		result = append(result, message)
+	}
+	err = rows.Err()
+	if err != nil {
+		return result, fmt.Errorf("error at loading outbox messages, case after iterating: %w", err)
+	}
+
+	// RETURNING does not keep the order of the subquery
+	sort.Slice(result, func(i, j int) bool { return result[i].Id < result[j].Id })
+
+	return result, nil
+}
+
+func MarkOutboxMessagePublished(tx *sql.Tx, ctx context.Context, id int) error {
+	_, err := tx.ExecContext(ctx, MARK_OUTBOX_MESSAGE_PUBLISHED_QUERY, id, time.Now())
+	if err != nil {
+		return fmt.Errorf("error at marking outbox message '%v' as published, case after executing statement: %w", id, err)
+	}
+	return nil
+}
+
+func MarkOutboxMessageFailed(tx *sql.Tx, ctx context.Context, id int, lastError string, nextAttemptDate time.Time) error {
+	_, err := tx.ExecContext(ctx, MARK_OUTBOX_MESSAGE_FAILED_QUERY, id, lastError, nextAttemptDate)
+	if err != nil {
+		return fmt.Errorf("error at marking outbox message '%v' as failed, case after executing statement: %w", id, err)
+	}
+	return nil
+}
+
+// DeletePublishedOutboxMessages deletes up to limit messages published before the date, returns the number of deleted messages
+func DeletePublishedOutboxMessages(tx *sql.Tx, ctx context.Context, publishedBefore time.Time, limit int) (int, error) {
+	res, err := tx.ExecContext(ctx, DELETE_PUBLISHED_OUTBOX_MESSAGES_QUERY, publishedBefore, limit)
+	if err != nil {
+		return 0, fmt.Errorf("error at deleting outbox messages published before '%v', case after executing statement: %w", publishedBefore, err)
+	}
+	affectedRowsCount, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error at deleting outbox messages published before '%v', case after counting affected rows: %w", publishedBefore, err)
+	}
+	return int(affectedRowsCount), nil
+}
@@ -20,6 +20,19 @@ type CreatePostParams struct {
 	Topic       interface{}
 }
 
+type GetPostsParams struct {
+	AfterCreateDate interface{}
+	AfterId         interface{}
+	Limit           int
+}
+
+type GetPostsFeedParams struct {
+	State            interface{}
+	BeforeCreateDate interface{}
+	BeforeUuid       interface{}
+	Limit            int
+}
+
 type UpdatePostParams struct {
 	Uuid        interface{}
 	AuthorUuid  interface{}
@@ -29,38 +42,46 @@ type UpdatePostParams struct {
 	State       interface{}
 }
 
-// TODO: add memory safe pagination without direct offset, use sorting by id and where criteria
-
 const (
 	GET_POSTS_QUERY = `SELECT 
 		id, uuid, author_uuid, text, preview_text, topic, state, create_date, last_update_date 
 	FROM posts 
-	WHERE state != $3 
-	LIMIT $1 OFFSET $2`
+	WHERE state != $1 AND ($2::timestamp IS NULL OR (create_date, id) > ($2::timestamp, $3::bigint))
+	ORDER BY create_date ASC, id ASC
+	LIMIT $4`
 
 	GET_POSTS_WITH_TAGS_QUERY = `SELECT 
 		posts.id, posts.uuid, posts.author_uuid, posts.text, posts.preview_text, posts.topic, posts.state, posts.create_date, posts.last_update_date, 
 		array_agg(posts_and_tags.tag_id) as tags
 	FROM posts 
 	LEFT OUTER JOIN posts_and_tags ON posts.id = posts_and_tags.post_id
-	WHERE state != $3 
+	WHERE state != $1 AND ($2::timestamp IS NULL OR (posts.create_date, posts.id) > ($2::timestamp, $3::bigint))
 	GROUP BY posts.id, posts.uuid, posts.author_uuid, posts.text, posts.preview_text, posts.topic, posts.state, posts.create_date, posts.last_update_date
-	ORDER BY posts.id ASC
-	LIMIT $1
-	OFFSET $2
+	ORDER BY posts.create_date ASC, posts.id ASC
+	LIMIT $4
 	`
 
+	GET_POSTS_FEED_QUERY = `SELECT 
+		posts.id, posts.uuid, posts.author_uuid, posts.text, posts.preview_text, posts.topic, posts.state, posts.create_date, posts.last_update_date, 
+		array_remove(array_agg(posts_and_tags.tag_id), NULL) as tags
+	FROM posts 
+	LEFT OUTER JOIN posts_and_tags ON posts.id = posts_and_tags.post_id
+	WHERE posts.state = $1 AND ($2::timestamp IS NULL OR (posts.create_date, posts.uuid) < ($2::timestamp, $3::uuid))
+	GROUP BY posts.id, posts.uuid, posts.author_uuid, posts.text, posts.preview_text, posts.topic, posts.state, posts.create_date, posts.last_update_date
+	ORDER BY posts.create_date DESC, posts.uuid DESC
+	LIMIT $4`
+
 	GET_POSTS_BY_IDS_QUERY = `SELECT 
 		id, uuid, author_uuid, text, preview_text, topic, state, create_date, last_update_date 
 	FROM posts 
-	WHERE state != $4 AND id = ANY($1)
-	LIMIT $2 OFFSET $3`
+	WHERE state != $2 AND id = ANY($1)
+	ORDER BY id ASC`
 
 	GET_POSTS_BY_UUIDS_QUERY = `SELECT 
 		id, uuid, author_uuid, text, preview_text, topic, state, create_date, last_update_date 
 	FROM posts 
-	WHERE state != $4 AND uuid = ANY($1)
-	LIMIT $2 OFFSET $3`
+	WHERE state != $2 AND uuid = ANY($1)
+	ORDER BY id ASC`
 
 	GET_POST_QUERY = `SELECT 
 		id, uuid, author_uuid, text, preview_text, topic, state, create_date, last_update_date 
@@ -72,6 +93,13 @@ const (
 	FROM posts 
 	WHERE uuid = $1 and state != $2`
 
+	// the post is locked until the end of transaction, so the concurrent changes of the state and the revisions are made one after another
+	LOCK_POST_QUERY_BY_UUID = `SELECT 
+		id, uuid, author_uuid, text, preview_text, topic, state, create_date, last_update_date 
+	FROM posts 
+	WHERE uuid = $1 and state != $2
+	FOR UPDATE`
+
 	GET_POST_WITH_TAGS_BY_UUID_QUERY = `SELECT 
 		posts.id, posts.uuid, posts.author_uuid, posts.text, posts.preview_text, posts.topic, posts.state, posts.create_date, posts.last_update_date, 
 		array_agg(posts_and_tags.tag_id) as tags 
@@ -110,9 +138,11 @@ const (
 	DELETE_POST_QUERY_BY_UUID = `UPDATE posts 
 	SET state = $2 
 	WHERE uuid = $1 and state != $2`
+
+	IS_POST_EXIST_QUERY = `SELECT EXISTS(SELECT 1 FROM posts WHERE uuid = $1)`
 )
 
-func GetPosts(tx *sql.Tx, ctx context.Context, limit int, offset int) ([]entities.Post, error) {
+func GetPosts(tx *sql.Tx, ctx context.Context, params *GetPostsParams) ([]entities.Post, error) {
 	var posts []entities.Post
 	var (
 		id             int
@@ -126,7 +156,7 @@ func GetPosts(tx *sql.Tx, ctx context.Context, limit int, offset int) ([]entitie
 		lastUpdateDate time.Time
 	)
 
-	rows, err := tx.QueryContext(ctx, GET_POSTS_QUERY, limit, offset, utilsEntities.POST_STATE_DELETED)
+	rows, err := tx.QueryContext(ctx, GET_POSTS_QUERY, utilsEntities.POST_STATE_DELETED, params.AfterCreateDate, params.AfterId, params.Limit)
 	if err != nil {
 		return posts, fmt.Errorf("error at loading posts, case after Query: %w", err)
 	}
@@ -147,7 +177,33 @@ func GetPosts(tx *sql.Tx, ctx context.Context, limit int, offset int) ([]entitie
 	return posts, nil
 }
 
-func GetPostsByIds(tx *sql.Tx, ctx context.Context, ids []int, limit int, offset int) ([]entities.Post, error) {
+func GetPostsFeed(tx *sql.Tx, ctx context.Context, params *GetPostsFeedParams) ([]entities.PostWithTagIds, error) {
+	var result []entities.PostWithTagIds
+
+	rows, err := tx.QueryContext(ctx, GET_POSTS_FEED_QUERY, params.State, params.BeforeCreateDate, params.BeforeUuid, params.Limit)
+	if err != nil {
+		return result, fmt.Errorf("error at loading posts feed, case after Query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var post entities.Post
+		var tagIds []int64
+		err := rows.Scan(&post.Id, &post.Uuid, &post.AuthorUuid, &post.Text, &post.PreviewText, &post.Topic, &post.State, &post.CreateDate, &post.LastUpdateDate, pq.Array(&tagIds))
+		if err != nil {
+			return result, fmt.Errorf("error at loading posts feed, case iterating and using rows.Scan: %w", err)
+		}
+		result = append(result, entities.PostWithTagIds{Post: post, TagIds: toInts(tagIds)})
+	}
+	err = rows.Err()
+	if err != nil {
+		return result, fmt.Errorf("error at loading posts feed, case after iterating: %w", err)
+	}
+
+	return result, nil
+}
+
+func GetPostsByIds(tx *sql.Tx, ctx context.Context, ids []int) ([]entities.Post, error) {
 	var posts []entities.Post
 	var (
 		id             int
@@ -160,14 +216,14 @@ func GetPostsByIds(tx *sql.Tx, ctx context.Context, ids []int, limit int, offset
 		createDate     time.Time
 		lastUpdateDate time.Time
 	)
-	rows, err := tx.QueryContext(ctx, GET_POSTS_BY_IDS_QUERY, pq.Array(ids), limit, offset, utilsEntities.POST_STATE_DELETED)
+	rows, err := tx.QueryContext(ctx, GET_POSTS_BY_IDS_QUERY, pq.Array(ids), utilsEntities.POST_STATE_DELETED)
 	if err != nil {
 		return posts, fmt.Errorf("error at loading posts by ids, case after Query: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		err := rows.Scan(&id, &authorUuid, &text, &previewText, &topic, &state, &createDate, &lastUpdateDate)
+		err := rows.Scan(&id, &uuid, &authorUuid, &text, &previewText, &topic, &state, &createDate, &lastUpdateDate)
 		if err != nil {
 			return posts, fmt.Errorf("error at loading posts by ids, case iterating and using rows.Scan: %w", err)
 		}
@@ -195,6 +251,21 @@ func GetPost(tx *sql.Tx, ctx context.Context, uuid string) (entities.Post, error
 	return post, nil
 }
 
+// LockPost returns the post and locks it until the end of transaction
+func LockPost(tx *sql.Tx, ctx context.Context, uuid string) (entities.Post, error) {
+	var post entities.Post
+
+	err := tx.QueryRowContext(ctx, LOCK_POST_QUERY_BY_UUID, uuid, utilsEntities.POST_STATE_DELETED).
+		Scan(&post.Id, &post.Uuid, &post.AuthorUuid, &post.Text, &post.PreviewText, &post.Topic, &post.State, &post.CreateDate, &post.LastUpdateDate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return post, err
+	} else if err != nil {
+		return post, fmt.Errorf("error at locking post by uuid '%v', case after QueryRow.Scan: %w", uuid, err)
+	}
+
+	return post, nil
+}
+
 func GetPostWithTagIds(tx *sql.Tx, ctx context.Context, uuid string) (entities.PostWithTagIds, error) {
 	var result entities.PostWithTagIds
 	post, err := GetPost(tx, ctx, uuid)
@@ -277,3 +348,21 @@ func DeletePost(tx *sql.Tx, ctx context.Context, uuid string) error {
 	}
 	return nil
 }
+
+// IsPostExist checks if the post is at the shard regardless of its state
+func IsPostExist(tx *sql.Tx, ctx context.Context, uuid string) (bool, error) {
+	var exists bool
+	err := tx.QueryRowContext(ctx, IS_POST_EXIST_QUERY, uuid).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("error at checking if post with uuid '%v' exists, case after QueryRow.Scan: %w", uuid, err)
+	}
+	return exists, nil
+}
+
+func toInts(input []int64) []int {
+	result := make([]int, len(input))
+	for i, v := range input {
+		result[i] = int(v)
+	}
+	return result
+}
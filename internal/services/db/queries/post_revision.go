@@ -0,0 +1,158 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+)
+
+type CreatePostRevisionParams struct {
+	PostUuid    string
+	Revision    int
+	Text        string
+	PreviewText string
+	Topic       string
+	EditorUuid  string
+	CreateDate  time.Time
+}
+
+type GetPostRevisionsParams struct {
+	PostUuid string
+	// BeforeRevision is the revision the page starts before, 0 means the latest one
+	BeforeRevision int
+	Limit          int
+}
+
+const (
+	CREATE_POST_REVISION_QUERY = `INSERT INTO post_revisions
+		(post_uuid, revision, text, preview_text, topic, editor_uuid, create_date)
+		VALUES($1, $2, $3, $4, $5, $6, $7)`
+
+	GET_LAST_POST_REVISION_QUERY = `SELECT COALESCE(MAX(revision), 0) FROM post_revisions WHERE post_uuid = $1`
+
+	// the list is without the texts, they are loaded by the revision
+	GET_POST_REVISIONS_QUERY = `SELECT
+		id, post_uuid, revision, topic, COALESCE(editor_uuid::text, ''), create_date
+	FROM post_revisions
+	WHERE post_uuid = $1 AND ($2 = 0 OR revision < $2)
+	ORDER BY revision DESC
+	LIMIT $3`
+
+	GET_POST_REVISION_QUERY = `SELECT
+		id, post_uuid, revision, text, preview_text, topic, COALESCE(editor_uuid::text, ''), create_date
+	FROM post_revisions
+	WHERE post_uuid = $1 AND revision = $2`
+
+	GET_ALL_POST_REVISIONS_QUERY = `SELECT
+		id, post_uuid, revision, text, preview_text, topic, COALESCE(editor_uuid::text, ''), create_date
+	FROM post_revisions
+	WHERE post_uuid = $1
+	ORDER BY revision`
+
+	// the revisions are copied by cmd/reshard, the ones copied on the previous passes are skipped
+	COPY_POST_REVISION_QUERY = `INSERT INTO post_revisions
+		(post_uuid, revision, text, preview_text, topic, editor_uuid, create_date)
+		VALUES($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (post_uuid, revision) DO NOTHING`
+
+	DELETE_POST_REVISIONS_QUERY = `DELETE FROM post_revisions WHERE post_uuid = $1`
+)
+
+func CreatePostRevision(tx *sql.Tx, ctx context.Context, params *CreatePostRevisionParams) error {
+	_, err := tx.ExecContext(ctx, CREATE_POST_REVISION_QUERY,
+		params.PostUuid, params.Revision, params.Text, params.PreviewText, params.Topic, toNullUuid(params.EditorUuid), params.CreateDate)
+	if err != nil {
+		return fmt.Errorf("error at inserting revision %v of post '%v', case after executing statement: %w", params.Revision, params.PostUuid, err)
+	}
+	return nil
+}
+
+// GetLastPostRevision returns 0 if the post has no revisions
+func GetLastPostRevision(tx *sql.Tx, ctx context.Context, postUuid string) (int, error) {
+	var revision int
+	err := tx.QueryRowContext(ctx, GET_LAST_POST_REVISION_QUERY, postUuid).Scan(&revision)
+	if err != nil {
+		return revision, fmt.Errorf("error at loading last revision of post '%v', case after QueryRow.Scan: %w", postUuid, err)
+	}
+	return revision, nil
+}
+
+func GetPostRevisions(tx *sql.Tx, ctx context.Context, params *GetPostRevisionsParams) ([]entities.PostRevision, error) {
+	var result []entities.PostRevision
+
+	rows, err := tx.QueryContext(ctx, GET_POST_REVISIONS_QUERY, params.PostUuid, params.BeforeRevision, params.Limit)
+	if err != nil {
+		return result, fmt.Errorf("error at loading revisions of post '%v', case after Query: %w", params.PostUuid, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r entities.PostRevision
+		err := rows.Scan(&r.Id, &r.PostUuid, &r.Revision, &r.Topic, &r.EditorUuid, &r.CreateDate)
+		if err != nil {
+			return result, fmt.Errorf("error at loading revisions of post '%v', case iterating and using rows.Scan: %w", params.PostUuid, err)
+		}
+		result = append(result, r)
+	}
+	err = rows.Err()
+	if err != nil {
+		return result, fmt.Errorf("error at loading revisions of post '%v', case after iterating: %w", params.PostUuid, err)
+	}
+
+	return result, nil
+}
+
+func GetPostRevision(tx *sql.Tx, ctx context.Context, postUuid string, revision int) (entities.PostRevision, error) {
+	var r entities.PostRevision
+
+	err := tx.QueryRowContext(ctx, GET_POST_REVISION_QUERY, postUuid, revision).
+		Scan(&r.Id, &r.PostUuid, &r.Revision, &r.Text, &r.PreviewText, &r.Topic, &r.EditorUuid, &r.CreateDate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return r, err
+	} else if err != nil {
+		return r, fmt.Errorf("error at loading revision %v of post '%v', case after QueryRow.Scan: %w", revision, postUuid, err)
+	}
+
+	return r, nil
+}
+
+// GetAllPostRevisions returns the revisions with the texts, it is used by cmd/reshard
+func GetAllPostRevisions(tx *sql.Tx, ctx context.Context, postUuid string) ([]entities.PostRevision, error) {
+	var result []entities.PostRevision
+
+	rows, err := tx.QueryContext(ctx, GET_ALL_POST_REVISIONS_QUERY, postUuid)
+	if err != nil {
+		return result, fmt.Errorf("error at loading all revisions of post '%v', case after Query: %w", postUuid, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r entities.PostRevision
+		err := rows.Scan(&r.Id, &r.PostUuid, &r.Revision, &r.Text, &r.PreviewText, &r.Topic, &r.EditorUuid, &r.CreateDate)
+		if err != nil {
+			return result, fmt.Errorf("error at loading all revisions of post '%v', case iterating and using rows.Scan: %w", postUuid, err)
+		}
+		result = append(result, r)
+	}
+	err = rows.Err()
+	if err != nil {
+		return result, fmt.Errorf("error at loading all revisions of post '%v', case after iterating: %w", postUuid, err)
+	}
+
+	return result, nil
+}
+
+// CopyPostRevisions writes the revisions of the moved post into the target shard
+func CopyPostRevisions(tx *sql.Tx, ctx context.Context, revisions []entities.PostRevision) error {
+	for _, r := range revisions {
+		_, err := tx.ExecContext(ctx, COPY_POST_REVISION_QUERY, r.PostUuid, r.Revision, r.Text, r.PreviewText, r.Topic, toNullUuid(r.EditorUuid), r.CreateDate)
+		if err != nil {
+			return fmt.Errorf("error at copying revision %v of post '%v', case after executing statement: %w", r.Revision, r.PostUuid, err)
+		}
+	}
+	return nil
+}
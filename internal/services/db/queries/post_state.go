@@ -0,0 +1,87 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+)
+
+type CreatePostStateTransitionParams struct {
+	PostUuid  string
+	FromState string
+	ToState   string
+	ActorUuid string
+}
+
+const (
+	// the date is taken by the database, the transitions of the post are ordered by the clock of its shard only
+	CREATE_POST_STATE_TRANSITION_QUERY = `INSERT INTO post_state_transitions
+		(post_uuid, from_state, to_state, actor_uuid, create_date)
+		VALUES($1, $2, $3, $4, now())`
+
+	GET_POST_STATE_TRANSITIONS_QUERY = `SELECT
+		id, post_uuid, from_state, to_state, COALESCE(actor_uuid::text, ''), create_date
+	FROM post_state_transitions
+	WHERE post_uuid = $1
+	ORDER BY create_date, id`
+
+	// the transitions are copied by cmd/reshard with the id of the source row, the ones copied on the previous passes are skipped.
+	// The dates are not unique, the transitions made in the same transaction share them
+	COPY_POST_STATE_TRANSITION_QUERY = `INSERT INTO post_state_transitions
+		(post_uuid, from_state, to_state, actor_uuid, create_date, source_id)
+		VALUES($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (post_uuid, source_id) DO NOTHING`
+
+	DELETE_POST_STATE_TRANSITIONS_QUERY = `DELETE FROM post_state_transitions WHERE post_uuid = $1`
+)
+
+func CreatePostStateTransition(tx *sql.Tx, ctx context.Context, params *CreatePostStateTransitionParams) error {
+	_, err := tx.ExecContext(ctx, CREATE_POST_STATE_TRANSITION_QUERY,
+		params.PostUuid, params.FromState, params.ToState, toNullUuid(params.ActorUuid))
+	if err != nil {
+		return fmt.Errorf("error at inserting state transition of post '%v' (%v -> %v), case after executing statement: %w", params.PostUuid, params.FromState, params.ToState, err)
+	}
+	return nil
+}
+
+func GetPostStateTransitions(tx *sql.Tx, ctx context.Context, postUuid string) ([]entities.PostStateTransition, error) {
+	var result []entities.PostStateTransition
+
+	rows, err := tx.QueryContext(ctx, GET_POST_STATE_TRANSITIONS_QUERY, postUuid)
+	if err != nil {
+		return result, fmt.Errorf("error at loading state transitions of post '%v', case after Query: %w", postUuid, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t entities.PostStateTransition
+		err := rows.Scan(&t.Id, &t.PostUuid, &t.FromState, &t.ToState, &t.ActorUuid, &t.CreateDate)
+		if err != nil {
+			return result, fmt.Errorf("error at loading state transitions of post '%v', case iterating and using rows.Scan: %w", postUuid, err)
+		}
+		result = append(result, t)
+	}
+	err = rows.Err()
+	if err != nil {
+		return result, fmt.Errorf("error at loading state transitions of post '%v', case after iterating: %w", postUuid, err)
+	}
+
+	return result, nil
+}
+
+// CopyPostStateTransitions writes the transitions of the moved post into the target shard
+func CopyPostStateTransitions(tx *sql.Tx, ctx context.Context, transitions []entities.PostStateTransition) error {
+	for _, t := range transitions {
+		_, err := tx.ExecContext(ctx, COPY_POST_STATE_TRANSITION_QUERY, t.PostUuid, t.FromState, t.ToState, toNullUuid(t.ActorUuid), t.CreateDate, t.Id)
+		if err != nil {
+			return fmt.Errorf("error at copying state transition of post '%v', case after executing statement: %w", t.PostUuid, err)
+		}
+	}
+	return nil
+}
+
+func toNullUuid(uuid string) sql.NullString {
+	return sql.NullString{String: uuid, Valid: uuid != ""}
+}
@@ -0,0 +1,35 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const (
+	// the replica which streams from the primary and replayed everything it received is not behind, even if the primary had no writes
+	// for a long time. The replica disconnected from the primary does not know how far behind it is, its lag is NULL.
+	// The primary itself has no lag. The status of the WAL receiver is visible to the roles with pg_read_all_stats only
+	GET_REPLICATION_LAG_QUERY = `SELECT CASE
+		WHEN NOT pg_is_in_recovery() THEN 0
+		WHEN NOT EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE status = 'streaming') THEN NULL
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`
+)
+
+// REPLICATION_LAG_UNKNOWN is the lag of the replica which does not stream from the primary
+const REPLICATION_LAG_UNKNOWN time.Duration = -1
+
+func GetReplicationLag(tx *sql.Tx, ctx context.Context) (time.Duration, error) {
+	var seconds sql.NullFloat64
+	err := tx.QueryRowContext(ctx, GET_REPLICATION_LAG_QUERY).Scan(&seconds)
+	if err != nil {
+		return 0, fmt.Errorf("error at loading replication lag, case after QueryRow.Scan: %w", err)
+	}
+	if !seconds.Valid {
+		return REPLICATION_LAG_UNKNOWN, nil
+	}
+	return time.Duration(seconds.Float64 * float64(time.Second)), nil
+}
@@ -0,0 +1,312 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/lib/pq"
+)
+
+// The queries below are used by cmd/reshard to move posts with their tags and comments between shards. Unlike the API ones
+// they see the posts and comments in all states
+
+type GetPostsForReshardParams struct {
+	AfterId int
+	Limit   int
+	// ChangedSince limits the batch by the posts which were updated or got updated comments since the date, zero date means all posts
+	ChangedSince time.Time
+}
+
+const (
+	GET_POSTS_FOR_RESHARD_QUERY = `SELECT
+		id, uuid, author_uuid, text, preview_text, topic, state, create_date, last_update_date
+	FROM posts
+	WHERE id > $1
+		AND ($3::timestamp IS NULL
+			OR last_update_date >= $3
+			OR uuid IN (SELECT post_uuid FROM comments WHERE last_update_date >= $3))
+	ORDER BY id
+	LIMIT $2`
+
+	GET_POST_FOR_RESHARD_QUERY = `SELECT
+		id, uuid, author_uuid, text, preview_text, topic, state, create_date, last_update_date
+	FROM posts
+	WHERE uuid = $1`
+
+	GET_POST_TAG_IDS_QUERY = `SELECT tag_id FROM posts_and_tags WHERE post_id = $1 ORDER BY tag_id`
+
+	GET_ALL_COMMENTS_BY_POST_UUID_QUERY = `SELECT
+		id, author_uuid, post_uuid, text, linked_comment_id, state, create_date, last_update_date, edit_count, edited_at
+	FROM comments
+	WHERE post_uuid = $1
+	ORDER BY id`
+
+	// the copy is applied only if it is not older than the existing row, so the updates made at the new shard are kept
+	UPSERT_POST_QUERY = `INSERT INTO posts
+		(uuid, author_uuid, text, preview_text, topic, state, create_date, last_update_date)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (uuid) DO UPDATE
+	SET author_uuid = EXCLUDED.author_uuid,
+		text = EXCLUDED.text,
+		preview_text = EXCLUDED.preview_text,
+		topic = EXCLUDED.topic,
+		state = EXCLUDED.state,
+		last_update_date = EXCLUDED.last_update_date
+	WHERE posts.last_update_date <= EXCLUDED.last_update_date
+	RETURNING id`
+
+	UPSERT_COMMENT_QUERY = `INSERT INTO comments
+		(id, author_uuid, post_uuid, text, linked_comment_id, state, create_date, last_update_date, edit_count, edited_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	ON CONFLICT (id) DO UPDATE
+	SET text = EXCLUDED.text,
+		linked_comment_id = EXCLUDED.linked_comment_id,
+		state = EXCLUDED.state,
+		last_update_date = EXCLUDED.last_update_date,
+		edit_count = EXCLUDED.edit_count,
+		edited_at = EXCLUDED.edited_at
+	WHERE comments.post_uuid = EXCLUDED.post_uuid AND comments.last_update_date <= EXCLUDED.last_update_date`
+
+	GET_COMMENT_POST_UUID_QUERY = `SELECT post_uuid FROM comments WHERE id = $1`
+
+	REPLACE_POST_TAGS_QUERY = `INSERT INTO posts_and_tags (post_id, tag_id) SELECT $1, unnest($2::bigint[])`
+
+	// the sequence could be behind the copied comments, so the greatest of both is taken
+	GET_COMMENTS_ID_SEQUENCE_QUERY = `SELECT GREATEST((SELECT last_value FROM comments_id_seq), (SELECT COALESCE(MAX(id), 0) FROM comments))`
+
+	SET_COMMENTS_ID_SEQUENCE_QUERY = `SELECT setval('comments_id_seq', $1)`
+
+	NEXT_COMMENT_ID_QUERY = `SELECT nextval('comments_id_seq')`
+
+	DELETE_ALL_COMMENTS_BY_POST_UUID_QUERY = `DELETE FROM comments WHERE post_uuid = $1`
+
+	DELETE_POST_BY_ID_QUERY = `DELETE FROM posts WHERE id = $1`
+)
+
+// ErrorCommentIdCollision means that the comment id is taken by a comment of another post at the target shard
+type ErrorCommentIdCollision struct {
+	CommentId int
+	PostUuid  string
+}
+
+func (e *ErrorCommentIdCollision) Error() string {
+	return fmt.Sprintf("comment id %v of post '%v' is taken at the target shard", e.CommentId, e.PostUuid)
+}
+
+func GetPostsForReshard(tx *sql.Tx, ctx context.Context, params *GetPostsForReshardParams) ([]entities.Post, error) {
+	var posts []entities.Post
+
+	var changedSince *time.Time
+	if !params.ChangedSince.IsZero() {
+		changedSince = &params.ChangedSince
+	}
+
+	rows, err := tx.QueryContext(ctx, GET_POSTS_FOR_RESHARD_QUERY, params.AfterId, params.Limit, changedSince)
+	if err != nil {
+		return posts, fmt.Errorf("error at loading posts for reshard, case after Query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var post entities.Post
+		err := rows.Scan(&post.Id, &post.Uuid, &post.AuthorUuid, &post.Text, &post.PreviewText, &post.Topic, &post.State, &post.CreateDate, &post.LastUpdateDate)
+		if err != nil {
+			return posts, fmt.Errorf("error at loading posts for reshard, case iterating and using rows.Scan: %w", err)
+		}
+		posts = append(posts, post)
+	}
+	err = rows.Err()
+	if err != nil {
+		return posts, fmt.Errorf("error at loading posts for reshard, case after iterating: %w", err)
+	}
+
+	return posts, nil
+}
+
+// GetPostForReshard returns the post with its tag ids and all comments
+func GetPostForReshard(tx *sql.Tx, ctx context.Context, uuid string) (entities.PostWithTagIds, []entities.Comment, error) {
+	var post entities.PostWithTagIds
+
+	err := tx.QueryRowContext(ctx, GET_POST_FOR_RESHARD_QUERY, uuid).
+		Scan(&post.Post.Id, &post.Post.Uuid, &post.Post.AuthorUuid, &post.Post.Text, &post.Post.PreviewText, &post.Post.Topic, &post.Post.State, &post.Post.CreateDate, &post.Post.LastUpdateDate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return post, nil, err
+		} else {
+			return post, nil, fmt.Errorf("error at loading post for reshard by uuid '%v', case after QueryRow.Scan: %w", uuid, err)
+		}
+	}
+
+	post.TagIds, err = getPostTagIds(tx, ctx, post.Post.Id)
+	if err != nil {
+		return post, nil, err
+	}
+
+	comments, err := getAllCommentsByPostUuid(tx, ctx, uuid)
+	if err != nil {
+		return post, nil, err
+	}
+
+	return post, comments, nil
+}
+
+func getPostTagIds(tx *sql.Tx, ctx context.Context, postId int) ([]int, error) {
+	var tagIds []int
+
+	rows, err := tx.QueryContext(ctx, GET_POST_TAG_IDS_QUERY, postId)
+	if err != nil {
+		return tagIds, fmt.Errorf("error at loading tag ids of post %v, case after Query: %w", postId, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tagId int
+		err := rows.Scan(&tagId)
+		if err != nil {
+			return tagIds, fmt.Errorf("error at loading tag ids of post %v, case iterating and using rows.Scan: %w", postId, err)
+		}
+		tagIds = append(tagIds, tagId)
+	}
+	err = rows.Err()
+	if err != nil {
+		return tagIds, fmt.Errorf("error at loading tag ids of post %v, case after iterating: %w", postId, err)
+	}
+
+	return tagIds, nil
+}
+
+func getAllCommentsByPostUuid(tx *sql.Tx, ctx context.Context, postUuid string) ([]entities.Comment, error) {
+	var comments []entities.Comment
+
+	rows, err := tx.QueryContext(ctx, GET_ALL_COMMENTS_BY_POST_UUID_QUERY, postUuid)
+	if err != nil {
+		return comments, fmt.Errorf("error at loading all comments by post uuid '%v', case after Query: %w", postUuid, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var comment entities.Comment
+		err := rows.Scan(&comment.Id, &comment.AuthorUuid, &comment.PostUuid, &comment.Text, &comment.LinkedCommentId, &comment.State, &comment.CreateDate, &comment.LastUpdateDate, &comment.EditCount, &comment.EditedAt)
+		if err != nil {
+			return comments, fmt.Errorf("error at loading all comments by post uuid '%v', case iterating and using rows.Scan: %w", postUuid, err)
+		}
+		comments = append(comments, comment)
+	}
+	err = rows.Err()
+	if err != nil {
+		return comments, fmt.Errorf("error at loading all comments by post uuid '%v', case after iterating: %w", postUuid, err)
+	}
+
+	return comments, nil
+}
+
+// CopyPost writes the post with its tags and comments into the target shard. Returns false if the target copy of the post is newer and was kept,
+// the comments are copied in both cases
+func CopyPost(tx *sql.Tx, ctx context.Context, post entities.PostWithTagIds, comments []entities.Comment) (bool, error) {
+	p := post.Post
+	var postId int
+	err := tx.QueryRowContext(ctx, UPSERT_POST_QUERY, p.Uuid, p.AuthorUuid, p.Text, p.PreviewText, p.Topic, p.State, p.CreateDate, p.LastUpdateDate).Scan(&postId)
+	// the comments are copied anyway, they are checked one by one
+	copied := !errors.Is(err, sql.ErrNoRows)
+	if err != nil && copied {
+		return false, fmt.Errorf("error at copying post '%v', case after QueryRow.Scan: %w", p.Uuid, err)
+	}
+
+	if copied {
+		_, err = tx.ExecContext(ctx, REMOVE_ALL_TAGS_FROM_POST_QUERY, postId)
+		if err != nil {
+			return false, fmt.Errorf("error at copying tags of post '%v', case after removing tags: %w", p.Uuid, err)
+		}
+		if len(post.TagIds) > 0 {
+			_, err = tx.ExecContext(ctx, REPLACE_POST_TAGS_QUERY, postId, pq.Array(post.TagIds))
+			if err != nil {
+				return false, fmt.Errorf("error at copying tags of post '%v', case after inserting tags: %w", p.Uuid, err)
+			}
+		}
+	}
+
+	for _, c := range comments {
+		res, err := tx.ExecContext(ctx, UPSERT_COMMENT_QUERY, c.Id, c.AuthorUuid, c.PostUuid, c.Text, c.LinkedCommentId, c.State, c.CreateDate, c.LastUpdateDate, c.EditCount, c.EditedAt)
+		if err != nil {
+			return false, fmt.Errorf("error at copying comment %v of post '%v', case after executing statement: %w", c.Id, p.Uuid, err)
+		}
+		affectedRowsCount, err := res.RowsAffected()
+		if err != nil {
+			return false, fmt.Errorf("error at copying comment %v of post '%v', case after counting affected rows: %w", c.Id, p.Uuid, err)
+		}
+		if affectedRowsCount > 0 {
+			continue
+		}
+		// nothing is written either because the target comment is newer or because the id belongs to another post
+		var existingPostUuid string
+		err = tx.QueryRowContext(ctx, GET_COMMENT_POST_UUID_QUERY, c.Id).Scan(&existingPostUuid)
+		if err != nil {
+			return false, fmt.Errorf("error at copying comment %v of post '%v', case after checking collision: %w", c.Id, p.Uuid, err)
+		}
+		if existingPostUuid != c.PostUuid {
+			return false, &ErrorCommentIdCollision{CommentId: c.Id, PostUuid: c.PostUuid}
+		}
+	}
+
+	return copied, nil
+}
+
+// DeletePostWithComments removes the post with its tags, comments, their edits, state transitions and revisions from the shard
+func DeletePostWithComments(tx *sql.Tx, ctx context.Context, post entities.Post) error {
+	_, err := tx.ExecContext(ctx, DELETE_ALL_COMMENT_EDITS_BY_POST_UUID_QUERY, post.Uuid)
+	if err != nil {
+		return fmt.Errorf("error at deleting comment edits of post '%v', case after executing statement: %w", post.Uuid, err)
+	}
+	_, err = tx.ExecContext(ctx, DELETE_POST_REVISIONS_QUERY, post.Uuid)
+	if err != nil {
+		return fmt.Errorf("error at deleting revisions of post '%v', case after executing statement: %w", post.Uuid, err)
+	}
+	_, err = tx.ExecContext(ctx, DELETE_POST_STATE_TRANSITIONS_QUERY, post.Uuid)
+	if err != nil {
+		return fmt.Errorf("error at deleting state transitions of post '%v', case after executing statement: %w", post.Uuid, err)
+	}
+	_, err = tx.ExecContext(ctx, DELETE_ALL_COMMENTS_BY_POST_UUID_QUERY, post.Uuid)
+	if err != nil {
+		return fmt.Errorf("error at deleting comments of post '%v', case after executing statement: %w", post.Uuid, err)
+	}
+	_, err = tx.ExecContext(ctx, REMOVE_ALL_TAGS_FROM_POST_QUERY, post.Id)
+	if err != nil {
+		return fmt.Errorf("error at deleting tags of post '%v', case after executing statement: %w", post.Uuid, err)
+	}
+	_, err = tx.ExecContext(ctx, DELETE_POST_BY_ID_QUERY, post.Id)
+	if err != nil {
+		return fmt.Errorf("error at deleting post '%v', case after executing statement: %w", post.Uuid, err)
+	}
+	return nil
+}
+
+func GetCommentsIdSequence(tx *sql.Tx, ctx context.Context) (int64, error) {
+	var value int64
+	err := tx.QueryRowContext(ctx, GET_COMMENTS_ID_SEQUENCE_QUERY).Scan(&value)
+	if err != nil {
+		return value, fmt.Errorf("error at loading comments id sequence, case after QueryRow.Scan: %w", err)
+	}
+	return value, nil
+}
+
+func SetCommentsIdSequence(tx *sql.Tx, ctx context.Context, value int64) error {
+	_, err := tx.ExecContext(ctx, SET_COMMENTS_ID_SEQUENCE_QUERY, value)
+	if err != nil {
+		return fmt.Errorf("error at setting comments id sequence to %v, case after executing statement: %w", value, err)
+	}
+	return nil
+}
+
+// NextCommentId takes the id from the comments sequence, it is used to move a comment whose id is taken at the target shard
+func NextCommentId(tx *sql.Tx, ctx context.Context) (int, error) {
+	var value int
+	err := tx.QueryRowContext(ctx, NEXT_COMMENT_ID_QUERY).Scan(&value)
+	if err != nil {
+		return value, fmt.Errorf("error at getting next comment id, case after QueryRow.Scan: %w", err)
+	}
+	return value, nil
+}
@@ -13,8 +13,17 @@ import (
 var ErrorTagDuplicateKey = errors.New("pq: duplicate key value violates unique constraint \"tags_name_unique\"")
 var ErrorPostTagDuplicateKey = errors.New("pq: duplicate key value violates unique constraint \"PK_posts_and_tags\"")
 
+// GetTagsParams is the page of tags after the tag with AfterId, the ids start from 1 so the first page is after 0
+type GetTagsParams struct {
+	AfterId int
+	Limit   int
+}
+
 const (
-	GET_TAGS_QUERY = `SELECT id, name FROM tags LIMIT $1 OFFSET $2`
+	GET_TAGS_QUERY = `SELECT id, name FROM tags WHERE id > $1 ORDER BY id ASC LIMIT $2`
+
+	// GET_TAGS_WITH_OFFSET_QUERY is kept for the gRPC clients which page the tags by offset
+	GET_TAGS_WITH_OFFSET_QUERY = `SELECT id, name FROM tags ORDER BY id ASC LIMIT $1 OFFSET $2`
 
 	GET_TAG_QUERY = `SELECT id, name FROM tags WHERE id = $1`
 
@@ -24,12 +33,25 @@ const (
 
 	DELETE_TAG_QUERY = `DELETE FROM tags WHERE id = $1`
 
+	// the tag is marked before it is detached from the posts, so it could not be assigned again in the meantime
+	MARK_TAG_DELETING_QUERY = `UPDATE tags SET deleting = TRUE WHERE id = $1`
+
 	ASSIGN_TAG_TO_POST_QUERY = `INSERT INTO posts_and_tags (post_id, tag_id) VALUES($1, $2)`
 
 	REMOVE_TAG_FROM_POST_QUERY = `DELETE FROM posts_and_tags WHERE post_id = $1 and tag_id = $2`
 
 	REMOVE_ALL_TAGS_FROM_POST_QUERY = `DELETE FROM posts_and_tags WHERE post_id = $1`
 
+	REMOVE_TAG_FROM_ALL_POSTS_QUERY = `DELETE FROM posts_and_tags WHERE tag_id = $1`
+
+	COUNT_POSTS_BY_TAG_ID_QUERY = `SELECT COUNT(*) FROM posts_and_tags WHERE tag_id = $1`
+
+	GET_POST_UUIDS_BY_TAG_ID_QUERY = `SELECT posts.uuid 
+    FROM posts_and_tags 
+    INNER JOIN posts ON posts_and_tags.post_id = posts.id
+    WHERE posts_and_tags.tag_id = $1;
+    `
+
 	GET_TAGS_BY_POST_ID_QUERY = `SELECT tags.id, tags.name 
     FROM (SELECT tag_id FROM posts_and_tags WHERE post_id = $1) as chosen_tags
     INNER JOIN tags ON chosen_tags.tag_id = tags.id;
@@ -43,20 +65,36 @@ const (
 	GET_TAGS_BY_IDS_QUERY = `SELECT tags.id, tags.name 
     FROM tags 
     WHERE tags.id = ANY($1::int[]);
+    `
+
+	GET_ASSIGNABLE_TAGS_BY_IDS_QUERY = `SELECT tags.id, tags.name 
+    FROM tags 
+    WHERE tags.id = ANY($1::int[]) AND NOT tags.deleting;
     `
 )
 
-func GetTags(tx *sql.Tx, ctx context.Context, limit int, offset int) ([]entities.Tag, error) {
+func GetTags(tx *sql.Tx, ctx context.Context, params *GetTagsParams) ([]entities.Tag, error) {
+	rows, err := tx.QueryContext(ctx, GET_TAGS_QUERY, params.AfterId, params.Limit)
+	if err != nil {
+		return make([]entities.Tag, 0), fmt.Errorf("error at loading tags from db, case after Query: %w", err)
+	}
+	return scanTags(rows)
+}
+
+func GetTagsWithOffset(tx *sql.Tx, ctx context.Context, offset int, limit int) ([]entities.Tag, error) {
+	rows, err := tx.QueryContext(ctx, GET_TAGS_WITH_OFFSET_QUERY, limit, offset)
+	if err != nil {
+		return make([]entities.Tag, 0), fmt.Errorf("error at loading tags from db, case after Query: %w", err)
+	}
+	return scanTags(rows)
+}
+
+func scanTags(rows *sql.Rows) ([]entities.Tag, error) {
 	var result []entities.Tag = make([]entities.Tag, 0)
 	var (
 		id   int
 		name string
 	)
-
-	rows, err := tx.QueryContext(ctx, GET_TAGS_QUERY, limit, offset)
-	if err != nil {
-		return result, fmt.Errorf("error at loading tags from db, case after Query: %w", err)
-	}
 	defer rows.Close()
 
 	for rows.Next() {
@@ -66,7 +104,7 @@ func GetTags(tx *sql.Tx, ctx context.Context, limit int, offset int) ([]entities
 		}
 		result = append(result, entities.Tag{Id: id, Name: name})
 	}
-	err = rows.Err()
+	err := rows.Err()
 	if err != nil {
 		return result, fmt.Errorf("error at loading tags from db, case after iterating: %w", err)
 	}
@@ -147,6 +185,21 @@ func DeleteTag(tx *sql.Tx, ctx context.Context, id int) error {
 	return nil
 }
 
+func MarkTagDeleting(tx *sql.Tx, ctx context.Context, id int) error {
+	res, err := tx.ExecContext(ctx, MARK_TAG_DELETING_QUERY, id)
+	if err != nil {
+		return fmt.Errorf("error at marking tag '%v' as deleting, case after executing statement: %w", id, err)
+	}
+	affectedRowsCount, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error at marking tag '%v' as deleting, case after counting affected rows: %w", id, err)
+	}
+	if affectedRowsCount == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
 func AssignTagToPost(tx *sql.Tx, ctx context.Context, postId int, tagId int) error {
 	stmt, err := tx.PrepareContext(ctx, ASSIGN_TAG_TO_POST_QUERY)
 	if err != nil {
@@ -202,6 +255,61 @@ func RemoveAllTagsFromPost(tx *sql.Tx, ctx context.Context, postId int) error {
 	return nil
 }
 
+func RemoveTagFromAllPosts(tx *sql.Tx, ctx context.Context, tagId int) (int, error) {
+	stmt, err := tx.PrepareContext(ctx, REMOVE_TAG_FROM_ALL_POSTS_QUERY)
+	if err != nil {
+		return 0, fmt.Errorf("error at deleting from posts_and_tags (TagId: '%v'), case after preparing statement: %w", tagId, err)
+	}
+	defer stmt.Close()
+	res, err := stmt.ExecContext(ctx, tagId)
+	if err != nil {
+		return 0, fmt.Errorf("error at deleting from posts_and_tags (TagId: '%v'), case after executing statement: %w", tagId, err)
+	}
+	affectedRowsCount, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error at deleting from posts_and_tags (TagId: '%v'), case after counting affected rows: %w", tagId, err)
+	}
+	return int(affectedRowsCount), nil
+}
+
+func CountPostsByTagId(tx *sql.Tx, ctx context.Context, tagId int) (int, error) {
+	var result int
+
+	err := tx.QueryRowContext(ctx, COUNT_POSTS_BY_TAG_ID_QUERY, tagId).Scan(&result)
+	if err != nil {
+		return result, fmt.Errorf("error at counting posts by tag id '%v', case after QueryRow.Scan: %w", tagId, err)
+	}
+
+	return result, nil
+}
+
+func GetPostUuidsByTagId(tx *sql.Tx, ctx context.Context, tagId int) ([]string, error) {
+	var result []string
+	var (
+		uuid string
+	)
+
+	rows, err := tx.QueryContext(ctx, GET_POST_UUIDS_BY_TAG_ID_QUERY, tagId)
+	if err != nil {
+		return result, fmt.Errorf("error at loading posts by tag id '%v' from db, case after Query: %w", tagId, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err := rows.Scan(&uuid)
+		if err != nil {
+			return result, fmt.Errorf("error at loading posts by tag id '%v' from db, case iterating and using rows.Scan: %w", tagId, err)
+		}
+		result = append(result, uuid)
+	}
+	err = rows.Err()
+	if err != nil {
+		return result, fmt.Errorf("error at loading posts by tag id '%v' from db, case after iterating: %w", tagId, err)
+	}
+
+	return result, nil
+}
+
 func GetTagIdsByPostId(tx *sql.Tx, ctx context.Context, postId int) ([]int, error) {
 	var result []int
 	var (
@@ -230,6 +338,15 @@ func GetTagIdsByPostId(tx *sql.Tx, ctx context.Context, postId int) ([]int, erro
 }
 
 func GetTagsByIds(tx *sql.Tx, ctx context.Context, tagIds []int) ([]entities.Tag, error) {
+	return getTagsByIds(tx, ctx, GET_TAGS_BY_IDS_QUERY, tagIds)
+}
+
+// GetAssignableTagsByIds skips the tags which are being deleted
+func GetAssignableTagsByIds(tx *sql.Tx, ctx context.Context, tagIds []int) ([]entities.Tag, error) {
+	return getTagsByIds(tx, ctx, GET_ASSIGNABLE_TAGS_BY_IDS_QUERY, tagIds)
+}
+
+func getTagsByIds(tx *sql.Tx, ctx context.Context, query string, tagIds []int) ([]entities.Tag, error) {
 	tagsConverted := make([]string, len(tagIds), len(tagIds))
 	for i, tagId := range tagIds {
 		tagsConverted[i] = fmt.Sprintf("%v", tagId)
@@ -242,7 +359,7 @@ func GetTagsByIds(tx *sql.Tx, ctx context.Context, tagIds []int) ([]entities.Tag
 		name string
 	)
 
-	rows, err := tx.QueryContext(ctx, GET_TAGS_BY_IDS_QUERY, tagsStr)
+	rows, err := tx.QueryContext(ctx, query, tagsStr)
 	if err != nil {
 		return result, fmt.Errorf("error at loading tags from db, case after Query: %w", err)
 	}
@@ -0,0 +1,208 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The line diff is found by the Myers algorithm, it is the shortest edit script, and formatted as the unified diff.
+// The lines are compared with their line breaks, so the missing line break at the end of the text is a change as well
+
+const contextLines = 3
+
+// maxEditDistance bounds the number of the changed lines. The trace of the algorithm takes O(D^2) memory, about 8 MB at the limit
+const maxEditDistance = 1000
+
+var ErrTooManyChanges = fmt.Errorf("the texts differ by more than %v lines", maxEditDistance)
+
+const noNewlineMarker = "\\ No newline at end of file\n"
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	line string
+}
+
+// Unified returns the unified diff of the texts with the given names at the headers, it is empty if the texts are equal.
+// ErrTooManyChanges is returned if the texts differ by more than maxEditDistance lines
+func Unified(fromName string, toName string, from string, to string) (string, error) {
+	if from == to {
+		return "", nil
+	}
+	ops, err := editScript(splitLines(from), splitLines(to))
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %v\n+++ %v\n", fromName, toName)
+	for _, h := range hunks(ops) {
+		writeHunk(&b, ops, h)
+	}
+	return b.String(), nil
+}
+
+// splitLines keeps the line breaks, only the last line could be without it
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	result := strings.SplitAfter(text, "\n")
+	// the text ending with the line break has no line after it
+	if result[len(result)-1] == "" {
+		result = result[:len(result)-1]
+	}
+	return result
+}
+
+// editScript walks the furthest reaching paths of every edit distance d, the trace keeps the diagonals -d-1..d+1
+// of every step, so the script is restored backwards without storing the whole edit graph
+func editScript(a []string, b []string) ([]op, error) {
+	n, m := len(a), len(b)
+	limit := n + m
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+
+	for d := 0; d <= limit; d++ {
+		if d > maxEditDistance {
+			return nil, ErrTooManyChanges
+		}
+		trace = append(trace, append([]int{}, v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b), nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func backtrack(trace [][]int, a []string, b []string) []op {
+	x, y := len(a), len(b)
+	var reversed []op
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		// the snapshot of the step d starts at the diagonal -d-1
+		at := func(k int) int { return v[k+d+1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, op{kind: opEqual, line: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, op{kind: opInsert, line: b[y-1]})
+			} else {
+				reversed = append(reversed, op{kind: opDelete, line: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	result := make([]op, len(reversed))
+	for i, o := range reversed {
+		result[len(reversed)-1-i] = o
+	}
+	return result
+}
+
+// hunk is the range of the ops with the changes and their context
+type hunk struct {
+	start int
+	end   int
+}
+
+func hunks(ops []op) []hunk {
+	var result []hunk
+	for i, o := range ops {
+		if o.kind == opEqual {
+			continue
+		}
+		start := max(0, i-contextLines)
+		end := min(len(ops), i+contextLines+1)
+		if len(result) > 0 && start <= result[len(result)-1].end {
+			result[len(result)-1].end = end
+			continue
+		}
+		result = append(result, hunk{start: start, end: end})
+	}
+	return result
+}
+
+func writeHunk(b *strings.Builder, ops []op, h hunk) {
+	// the lines of both texts before the hunk
+	fromLine, toLine := 0, 0
+	for _, o := range ops[:h.start] {
+		if o.kind != opInsert {
+			fromLine++
+		}
+		if o.kind != opDelete {
+			toLine++
+		}
+	}
+
+	fromCount, toCount := 0, 0
+	for _, o := range ops[h.start:h.end] {
+		if o.kind != opInsert {
+			fromCount++
+		}
+		if o.kind != opDelete {
+			toCount++
+		}
+	}
+	// the empty range is given by the line before it
+	if fromCount > 0 {
+		fromLine++
+	}
+	if toCount > 0 {
+		toLine++
+	}
+
+	fmt.Fprintf(b, "@@ -%v,%v +%v,%v @@\n", fromLine, fromCount, toLine, toCount)
+	for _, o := range ops[h.start:h.end] {
+		switch o.kind {
+		case opEqual:
+			b.WriteString(" ")
+		case opDelete:
+			b.WriteString("-")
+		case opInsert:
+			b.WriteString("+")
+		}
+		b.WriteString(o.line)
+		if !strings.HasSuffix(o.line, "\n") {
+			b.WriteString("\n")
+			b.WriteString(noNewlineMarker)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package diff
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func lines(from int, to int) string {
+	var b strings.Builder
+	for i := from; i <= to; i++ {
+		b.WriteString("line ")
+		b.WriteString(string(rune('a' + i - 1)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected string
+	}{
+		{
+			name:     "equal",
+			from:     "a\nb\n",
+			to:       "a\nb\n",
+			expected: "",
+		},
+		{
+			name:     "empty to text",
+			from:     "",
+			to:       "a\nb\n",
+			expected: "--- from\n+++ to\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:     "text to empty",
+			from:     "a\nb\n",
+			to:       "",
+			expected: "--- from\n+++ to\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name:     "changed line",
+			from:     "a\nb\nc\n",
+			to:       "a\nx\nc\n",
+			expected: "--- from\n+++ to\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		{
+			name:     "inserted line",
+			from:     "a\nb\n",
+			to:       "a\nx\nb\n",
+			expected: "--- from\n+++ to\n@@ -1,2 +1,3 @@\n a\n+x\n b\n",
+		},
+		{
+			name:     "inserted into empty text has no context",
+			from:     "",
+			to:       "x",
+			expected: "--- from\n+++ to\n@@ -0,0 +1,1 @@\n+x\n\\ No newline at end of file\n",
+		},
+		{
+			name:     "trailing newline added",
+			from:     "a\nb",
+			to:       "a\nb\n",
+			expected: "--- from\n+++ to\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{
+			name:     "trailing newline removed",
+			from:     "a\nb\n",
+			to:       "a\nb",
+			expected: "--- from\n+++ to\n@@ -1,2 +1,2 @@\n a\n-b\n+b\n\\ No newline at end of file\n",
+		},
+		{
+			name: "hunks merge at exactly two contexts apart",
+			// six equal lines between the changes, the contexts of both changes meet
+			from:     "x\n" + lines(1, 6) + "y\n",
+			to:       "X\n" + lines(1, 6) + "Y\n",
+			expected: "--- from\n+++ to\n@@ -1,8 +1,8 @@\n-x\n+X\n" + prefixed(" ", lines(1, 6)) + "-y\n+Y\n",
+		},
+		{
+			name: "hunks split further than two contexts apart",
+			// seven equal lines between the changes, the line in the middle is out of both contexts
+			from: "x\n" + lines(1, 7) + "y\n",
+			to:   "X\n" + lines(1, 7) + "Y\n",
+			expected: "--- from\n+++ to\n" +
+				"@@ -1,4 +1,4 @@\n-x\n+X\n" + prefixed(" ", lines(1, 3)) +
+				"@@ -6,4 +6,4 @@\n" + prefixed(" ", lines(5, 7)) + "-y\n+Y\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Unified("from", "to", tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Fatalf("expected:\n%q\ngot:\n%q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestUnifiedTooManyChanges(t *testing.T) {
+	from := strings.Repeat("a\n", maxEditDistance/2+1)
+	to := strings.Repeat("b\n", maxEditDistance/2+1)
+	_, err := Unified("from", "to", from, to)
+	if !errors.Is(err, ErrTooManyChanges) {
+		t.Fatalf("expected ErrTooManyChanges, got %v", err)
+	}
+
+	// the long texts with a few changes are compared
+	from = strings.Repeat("a\n", 10*maxEditDistance)
+	to = "b\n" + from
+	result, err := Unified("from", "to", from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "--- from\n+++ to\n@@ -1,3 +1,4 @@\n+b\n a\n a\n a\n" {
+		t.Fatalf("unexpected diff: %q", result)
+	}
+}
+
+func prefixed(prefix string, text string) string {
+	var b strings.Builder
+	for _, line := range splitLines(text) {
+		b.WriteString(prefix)
+		b.WriteString(line)
+	}
+	return b.String()
+}
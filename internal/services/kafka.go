@@ -1,15 +1,31 @@
 package services
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/kafka"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/metrics"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/tracing"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func SendMessageToKafkaQueue(queueTopic string, message string) {
-	err := Instance().KafkaProducer().CreateMessage(queueTopic, message)
-	if err != nil {
-		// TODO: create some daemon that catch unpublished posts
-		log.Error(fmt.Sprintf("Unable to put message '%v' into queue '%v'", message, queueTopic), err.Error())
+// KafkaPublisher returns the function sending the messages through the producer within the producer span,
+// the consumers continue the trace from the message headers
+func KafkaPublisher(producer *kafka.KafkaProducerService) func(ctx context.Context, queueTopic string, message string) error {
+	return func(ctx context.Context, queueTopic string, message string) error {
+		ctx, span := tracing.Start(ctx, queueTopic+" publish",
+			trace.WithSpanKind(trace.SpanKindProducer),
+			trace.WithAttributes(semconv.MessagingSystemKey.String("kafka"), semconv.MessagingDestinationNameKey.String(queueTopic)),
+		)
+		err := producer.CreateMessage(queueTopic, message, tracing.Inject(ctx))
+		tracing.End(span, err)
+		metrics.CountKafkaMessage(queueTopic, err)
+		if err != nil {
+			log.Error(fmt.Sprintf("Unable to put message '%v' into queue '%v'", message, queueTopic), err.Error())
+		}
+		return err
 	}
 }
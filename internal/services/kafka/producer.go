@@ -0,0 +1,86 @@
+package kafka
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// The producer of the utils sends the values only, this one sends the headers as well, they carry the trace context
+
+const shutdownFlushTimeout = 15 * time.Second
+
+type KafkaProducerService struct {
+	producer *kafka.Producer
+	wg       sync.WaitGroup
+}
+
+// CreateKafkaProducerService connects to the brokers, the message which is not delivered within KAFKA_DELIVERY_TIMEOUT_IN_SECONDS fails
+func CreateKafkaProducerService(host string) (*KafkaProducerService, error) {
+	deliveryTimeout := utils.EnvVarDurationDefault("KAFKA_DELIVERY_TIMEOUT_IN_SECONDS", time.Second, 10*time.Second)
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers":  host,
+		"message.timeout.ms": int(deliveryTimeout.Milliseconds()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create kafka producer: %w", err)
+	}
+
+	result := &KafkaProducerService{producer: producer}
+	result.wg.Add(1)
+	go result.logEvents()
+	return result, nil
+}
+
+// CreateMessage sends the message and waits for its delivery
+func (s *KafkaProducerService) CreateMessage(topic string, message string, headers map[string]string) error {
+	delivery := make(chan kafka.Event, 1)
+	err := s.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Value:          []byte(message),
+		Headers:        toHeaders(headers),
+	}, delivery)
+	if err != nil {
+		return fmt.Errorf("unable to produce message into topic '%v': %w", topic, err)
+	}
+
+	event := <-delivery
+	delivered, ok := event.(*kafka.Message)
+	if !ok {
+		return fmt.Errorf("unexpected delivery event: %v", event)
+	}
+	if delivered.TopicPartition.Error != nil {
+		return fmt.Errorf("unable to deliver message into topic '%v': %w", topic, delivered.TopicPartition.Error)
+	}
+	return nil
+}
+
+func (s *KafkaProducerService) Shutdown() error {
+	s.producer.Flush(int(shutdownFlushTimeout.Milliseconds()))
+	// closes the events channel as well
+	s.producer.Close()
+	s.wg.Wait()
+	return nil
+}
+
+// logEvents drains the events other than the delivery reports, those are sent to the channels given on produce
+func (s *KafkaProducerService) logEvents() {
+	defer s.wg.Done()
+	for event := range s.producer.Events() {
+		if err, ok := event.(kafka.Error); ok {
+			log.Error("Kafka producer error", err.Error())
+		}
+	}
+}
+
+func toHeaders(headers map[string]string) []kafka.Header {
+	result := make([]kafka.Header, 0, len(headers))
+	for key, value := range headers {
+		result = append(result, kafka.Header{Key: key, Value: []byte(value)})
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// The metrics are registered at the default registry and exposed by /metrics
+
+const namespace = "posts"
+
+const (
+	CACHE_RESULT_HIT   = "hit"
+	CACHE_RESULT_MISS  = "miss"
+	CACHE_RESULT_ERROR = "error"
+
+	DB_ERROR_REJECTED   = "rejected"
+	DB_ERROR_CONNECTION = "connection"
+	DB_ERROR_QUERY      = "query"
+	DB_ERROR_CANCELED   = "canceled"
+
+	KAFKA_RESULT_SUCCESS = "success"
+	KAFKA_RESULT_FAILURE = "failure"
+)
+
+var (
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of the REST requests by route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	grpcRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "Duration of the gRPC calls by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Duration of the transactions by shard.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"shard"})
+
+	dbQueryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Failed transactions by shard and kind: rejected by the circuit breaker, connection failures, query errors and canceled by the caller.",
+	}, []string{"shard", "kind"})
+
+	cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Cache reads by layer (l1, redis) and result (hit, miss, error).",
+	}, []string{"layer", "result"})
+
+	redisCommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "redis_command_duration_seconds",
+		Help:      "Duration of the Redis round trips by operation.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation"})
+
+	kafkaMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kafka_messages_total",
+		Help:      "Messages published into Kafka by topic and result.",
+	}, []string{"topic", "result"})
+)
+
+// HTTPMiddleware observes the duration of the requests, the routes are the registered patterns so the uuids don't blow up the labels
+func HTTPMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unknown"
+		}
+		httpRequestDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveGRPC is deferred by the gRPC handlers with the named error result, e.g. defer metrics.ObserveGRPC(ctx, time.Now(), &err)
+func ObserveGRPC(ctx context.Context, start time.Time, err *error) {
+	method, ok := grpc.Method(ctx)
+	if !ok {
+		method = "unknown"
+	}
+	grpcRequestDuration.WithLabelValues(method, status.Code(*err).String()).Observe(time.Since(start).Seconds())
+}
+
+func ObserveDBQuery(shard string, start time.Time) {
+	dbQueryDuration.WithLabelValues(shard).Observe(time.Since(start).Seconds())
+}
+
+func CountDBError(shard string, kind string) {
+	dbQueryErrors.WithLabelValues(shard, kind).Inc()
+}
+
+func CountCacheRequest(layer string, result string) {
+	cacheRequests.WithLabelValues(layer, result).Inc()
+}
+
+func ObserveRedisCommand(operation string, start time.Time) {
+	redisCommandDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+func CountKafkaMessage(topic string, err error) {
+	result := KAFKA_RESULT_SUCCESS
+	if err != nil {
+		result = KAFKA_RESULT_FAILURE
+	}
+	kafkaMessages.WithLabelValues(topic, result).Inc()
+}
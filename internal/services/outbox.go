@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/posts"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
+)
+
+// OutboxRelay periodically publishes events stored at the outbox tables of all posts shards into Kafka.
+// The published events are kept for OUTBOX_RETENTION_IN_HOURS and then deleted
+type OutboxRelay struct {
+	posts         *posts.PostsService
+	publish       func(ctx context.Context, topic string, payload string) error
+	interval      time.Duration
+	batchSize     int
+	minBackoff    time.Duration
+	maxBackoff    time.Duration
+	lease         time.Duration
+	retention     time.Duration
+	pruneInterval time.Duration
+	quit          chan struct{}
+	wg            sync.WaitGroup
+}
+
+func CreateOutboxRelay(postsService *posts.PostsService, publish func(ctx context.Context, topic string, payload string) error) *OutboxRelay {
+	batchSize, err := strconv.Atoi(utils.EnvVarDefault("OUTBOX_RELAY_BATCH_SIZE", "100"))
+	if err != nil || batchSize <= 0 {
+		batchSize = 100
+	}
+	return &OutboxRelay{
+		posts:         postsService,
+		publish:       publish,
+		interval:      utils.EnvVarDurationDefault("OUTBOX_RELAY_INTERVAL_IN_SECONDS", time.Second, time.Second),
+		batchSize:     batchSize,
+		minBackoff:    time.Second,
+		maxBackoff:    utils.EnvVarDurationDefault("OUTBOX_RELAY_MAX_BACKOFF_IN_SECONDS", time.Second, 5*time.Minute),
+		lease:         utils.EnvVarDurationDefault("OUTBOX_RELAY_LEASE_IN_SECONDS", time.Second, 5*time.Minute),
+		retention:     utils.EnvVarDurationDefault("OUTBOX_RETENTION_IN_HOURS", time.Hour, 7*24*time.Hour),
+		pruneInterval: utils.EnvVarDurationDefault("OUTBOX_PRUNE_INTERVAL_IN_SECONDS", time.Second, 10*time.Minute),
+		quit:          make(chan struct{}),
+	}
+}
+
+func (r *OutboxRelay) Start() {
+	r.wg.Add(1)
+	go r.relay()
+}
+
+func (r *OutboxRelay) Shutdown() error {
+	close(r.quit)
+	r.wg.Wait()
+	return nil
+}
+
+// relay drains the shards in parallel on every tick, the number of shards is taken anew since the topology could be reloaded
+func (r *OutboxRelay) relay() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	pruneTicker := time.NewTicker(r.pruneInterval)
+	defer pruneTicker.Stop()
+	for {
+		select {
+		case <-r.quit:
+			return
+		case <-pruneTicker.C:
+			for i := 0; i < r.posts.ShardsCount(); i++ {
+				r.pruneShard(i)
+			}
+		case <-ticker.C:
+			var wg sync.WaitGroup
+			for i := 0; i < r.posts.ShardsCount(); i++ {
+				wg.Add(1)
+				go func(shard int) {
+					defer wg.Done()
+					r.relayShard(shard)
+				}(i)
+			}
+			wg.Wait()
+		}
+	}
+}
+
+// relayShard drains the backlog of the shard without waiting for the next tick
+func (r *OutboxRelay) relayShard(shard int) {
+	for {
+		published, err := r.posts.RelayOutbox(context.Background(), shard, r.batchSize, r.lease, r.publish, r.backoff)
+		if err != nil {
+			log.Error(fmt.Sprintf("Unable to relay outbox messages of shard %v", shard), err.Error())
+			return
+		}
+		if published < r.batchSize {
+			return
+		}
+		select {
+		case <-r.quit:
+			return
+		default:
+		}
+	}
+}
+
+// pruneShard deletes the messages published before the retention period in batches
+func (r *OutboxRelay) pruneShard(shard int) {
+	publishedBefore := time.Now().Add(-r.retention)
+	for {
+		deleted, err := r.posts.PruneOutbox(context.Background(), shard, publishedBefore, r.batchSize)
+		if err != nil {
+			log.Error(fmt.Sprintf("Unable to prune outbox messages of shard %v", shard), err.Error())
+			return
+		}
+		if deleted < r.batchSize {
+			return
+		}
+		select {
+		case <-r.quit:
+			return
+		default:
+		}
+	}
+}
+
+// backoff grows exponentially with the number of attempts up to maxBackoff, jitter spreads retries of messages failed at the same time
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	result := r.maxBackoff
+	if attempts < 32 {
+		result = r.minBackoff << (attempts - 1)
+	}
+	if result <= 0 || result > r.maxBackoff {
+		result = r.maxBackoff
+	}
+	return result + time.Duration(rand.Int63n(int64(result)/5+1))
+}
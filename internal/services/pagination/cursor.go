@@ -0,0 +1,76 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+var ErrEmptyCursorSecret = errors.New("the secret of the cursors is empty, the signature could be forged")
+
+// Cursor is a keyset pagination position: the sort key of the last item of the previous page
+type Cursor struct {
+	CreateDate time.Time `json:"d,omitempty"`
+	Id         int       `json:"i,omitempty"`
+	Uuid       string    `json:"u,omitempty"`
+}
+
+// CursorService converts cursors into opaque signed tokens and back, so clients could not forge or tweak them
+type CursorService struct {
+	secret []byte
+}
+
+func CreateCursorService(secret string) (*CursorService, error) {
+	if secret == "" {
+		return nil, ErrEmptyCursorSecret
+	}
+	return &CursorService{
+		secret: []byte(secret),
+	}, nil
+}
+
+func (s *CursorService) Encode(cursor Cursor) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal cursor: %w", err)
+	}
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	return encodedPayload + "." + base64.RawURLEncoding.EncodeToString(s.sign(encodedPayload)), nil
+}
+
+func (s *CursorService) Decode(token string) (*Cursor, error) {
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return nil, ErrInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if !hmac.Equal(signature, s.sign(encodedPayload)) {
+		return nil, ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var result Cursor
+	err = json.Unmarshal(payload, &result)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &result, nil
+}
+
+func (s *CursorService) sign(encodedPayload string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(encodedPayload))
+	return mac.Sum(nil)
+}
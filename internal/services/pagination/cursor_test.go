@@ -0,0 +1,97 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createTestCursorService(t *testing.T, secret string) *CursorService {
+	t.Helper()
+	s, err := CreateCursorService(secret)
+	if err != nil {
+		t.Fatalf("unable to create cursor service: %v", err)
+	}
+	return s
+}
+
+func TestCreateCursorServiceRejectsEmptySecret(t *testing.T) {
+	_, err := CreateCursorService("")
+	if !errors.Is(err, ErrEmptyCursorSecret) {
+		t.Fatalf("expected ErrEmptyCursorSecret, got %v", err)
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	s := createTestCursorService(t, "secret")
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{name: "id", cursor: Cursor{Id: 42}},
+		{name: "create date and id", cursor: Cursor{CreateDate: time.Date(2024, 3, 27, 8, 57, 57, 123456000, time.UTC), Id: 7}},
+		{name: "create date and uuid", cursor: Cursor{CreateDate: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Uuid: "9b4f4363-6a3e-4c1b-8e2d-1f2a3b4c5d6e"}},
+		{name: "empty", cursor: Cursor{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := s.Encode(tt.cursor)
+			if err != nil {
+				t.Fatalf("unable to encode: %v", err)
+			}
+			decoded, err := s.Decode(token)
+			if err != nil {
+				t.Fatalf("unable to decode %q: %v", token, err)
+			}
+			if !decoded.CreateDate.Equal(tt.cursor.CreateDate) || decoded.Id != tt.cursor.Id || decoded.Uuid != tt.cursor.Uuid {
+				t.Fatalf("expected %+v, got %+v", tt.cursor, *decoded)
+			}
+		})
+	}
+}
+
+func TestDecodeRejectsTamperedCursors(t *testing.T) {
+	s := createTestCursorService(t, "secret")
+	token, err := s.Encode(Cursor{Id: 42})
+	if err != nil {
+		t.Fatalf("unable to encode: %v", err)
+	}
+	payload, signature, _ := strings.Cut(token, ".")
+	forgedPayload := base64.RawURLEncoding.EncodeToString([]byte(`{"i":43}`))
+
+	other := createTestCursorService(t, "another secret")
+	signedByOther, err := other.Encode(Cursor{Id: 42})
+	if err != nil {
+		t.Fatalf("unable to encode: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "without signature", token: payload},
+		{name: "empty signature", token: payload + "."},
+		{name: "forged payload", token: forgedPayload + "." + signature},
+		{name: "truncated signature", token: payload + "." + signature[:len(signature)-2]},
+		{name: "signature is not base64", token: payload + ".!!!"},
+		{name: "signed with another secret", token: signedByOther},
+		{name: "payload is not json", token: signedPayload(s, "bm90IGpzb24")},
+		{name: "payload is not base64", token: signedPayload(s, "!!!")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Decode(tt.token)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("expected ErrInvalidCursor, got %v", err)
+			}
+		})
+	}
+}
+
+// signedPayload signs the raw payload, so the checks after the signature are reached
+func signedPayload(s *CursorService, encodedPayload string) string {
+	return encodedPayload + "." + base64.RawURLEncoding.EncodeToString(s.sign(encodedPayload))
+}
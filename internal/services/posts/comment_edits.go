@@ -0,0 +1,61 @@
+package posts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/queries"
+)
+
+// The text of the comment replaced by the edit is stored at comment_edits of the shard in the same transaction as the comment.
+// The edits are numbered from 1, the comment keeps their count and the date of the last one. The change of the state is not the edit
+
+// updateComment locks the comment so the edits are numbered in the order they are made
+func updateComment(tx *sql.Tx, ctx context.Context, editorUuid string, postUuid string, commentId int, text *string, state *string) error {
+	comment, err := queries.LockComment(tx, ctx, postUuid, commentId)
+	if err != nil {
+		return err
+	}
+
+	if text != nil && *text != comment.Text {
+		err = queries.CreateCommentEdit(tx, ctx, &queries.CreateCommentEditParams{
+			CommentId:  comment.Id,
+			PostUuid:   comment.PostUuid,
+			Edit:       comment.EditCount + 1,
+			Text:       comment.Text,
+			EditorUuid: editorUuid,
+			CreateDate: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return queries.UpdateComment(tx, ctx, &queries.UpdateCommentParams{
+		Id:       commentId,
+		PostUuid: postUuid,
+		Text:     text,
+		State:    state,
+	})
+}
+
+// GetCommentEdits returns the previous texts of the comment from the first edit to the last one
+func (s *PostsService) GetCommentEdits(ctx context.Context, postUuid string, commentId int) ([]entities.CommentEdit, error) {
+	var result []entities.CommentEdit
+
+	data, err := s.getReadClientPostsShard(ctx, postUuid).Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		return queries.GetCommentEdits(tx, ctx, postUuid, commentId)
+	})()
+	if err != nil {
+		return result, err
+	}
+
+	result, ok := data.([]entities.CommentEdit)
+	if !ok {
+		return result, fmt.Errorf("unable to convert result into []entities.CommentEdit")
+	}
+	return result, nil
+}
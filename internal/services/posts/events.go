@@ -0,0 +1,205 @@
+package posts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/queries"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/tracing"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+)
+
+const NewPostsTopic = "new_posts"
+const UpdatedPostsStatesTopic = "updated_posts_states"
+const UpdatedPostsTagsTopic = "updated_posts_tags"
+const DeletedPostsTopic = "deleted_posts"
+
+const NewCommentsTopic = "new_comments"
+const UpdatedCommentsStatesTopic = "updated_comments_states"
+const DeletedCommentsTopic = "deleted_comments"
+
+// Events are not sent to Kafka directly. They are stored at the outbox table of the post shard within the same transaction as the change itself
+// and then published by the outbox relay, so an event is never lost if Kafka is unavailable and never sent for a rolled back change.
+
+func writePostEvents(tx *sql.Tx, ctx context.Context, postUuid string, topics ...string) error {
+	if len(topics) == 0 {
+		return nil
+	}
+	post, err := queries.GetPostWithTagIds(tx, ctx, postUuid)
+	if err != nil {
+		return err
+	}
+	postWithTagsForQueue := entities.PostWithTagsForQueue{
+		PostUuid:   post.Post.Uuid,
+		AuthorUuid: post.Post.AuthorUuid,
+		CreateDate: post.Post.CreateDate,
+		State:      post.Post.State,
+		TagIds:     post.TagIds,
+	}
+	postJSON, err := json.Marshal(postWithTagsForQueue)
+	if err != nil {
+		return fmt.Errorf("unable to convert post with uuid '%v' to JSON: %w", postUuid, err)
+	}
+	return writeEvents(tx, ctx, string(postJSON), topics...)
+}
+
+func writeCommentEvents(tx *sql.Tx, ctx context.Context, postUuid string, commentId int, topics ...string) error {
+	if len(topics) == 0 {
+		return nil
+	}
+	comment, err := queries.GetComment(tx, ctx, postUuid, commentId)
+	if err != nil {
+		return err
+	}
+	commentForQueue := entities.CommentForQueue{
+		PostUuid:   comment.PostUuid,
+		CommentId:  comment.Id,
+		CreateDate: comment.CreateDate,
+		State:      comment.State,
+	}
+	commentJSON, err := json.Marshal(commentForQueue)
+	if err != nil {
+		return fmt.Errorf("unable to convert comment '%v' to JSON: %w", commentForQueue, err)
+	}
+	return writeEvents(tx, ctx, string(commentJSON), topics...)
+}
+
+func writeDeletedCommentEvents(tx *sql.Tx, ctx context.Context, postUuid string, commentId int, topics ...string) error {
+	if len(topics) == 0 {
+		return nil
+	}
+	commentForQueue := entities.DeletedCommentForQueue{
+		PostUuid:  postUuid,
+		CommentId: commentId,
+	}
+	commentJSON, err := json.Marshal(commentForQueue)
+	if err != nil {
+		return fmt.Errorf("unable to convert comment '%v' to JSON: %w", commentForQueue, err)
+	}
+	return writeEvents(tx, ctx, string(commentJSON), topics...)
+}
+
+// writeEvents stores the trace context of the transaction together with the events, so the relay sends it in the message headers
+func writeEvents(tx *sql.Tx, ctx context.Context, payload string, topics ...string) error {
+	traceContext, err := encodeTraceContext(tracing.Inject(ctx))
+	if err != nil {
+		return err
+	}
+	for _, topic := range topics {
+		_, err := queries.CreateOutboxMessage(tx, ctx, topic, payload, traceContext)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeTraceContext(headers map[string]string) (string, error) {
+	if len(headers) == 0 {
+		return "", nil
+	}
+	result, err := json.Marshal(headers)
+	if err != nil {
+		return "", fmt.Errorf("unable to convert trace context to JSON: %w", err)
+	}
+	return string(result), nil
+}
+
+// decodeTraceContext returns no headers for the malformed trace context, the message is sent without it
+func decodeTraceContext(traceContext string) map[string]string {
+	var result map[string]string
+	if traceContext == "" {
+		return result
+	}
+	err := json.Unmarshal([]byte(traceContext), &result)
+	if err != nil {
+		log.Error("Unable to unmarshal trace context of outbox message", err.Error())
+	}
+	return result
+}
+
+// RelayOutbox publishes up to limit pending outbox messages of the shard. Failed messages are postponed according to the backoff function.
+// The messages are claimed for the lease in a short transaction, published outside of it and then marked in another one,
+// so the rows are not locked while Kafka delivers the messages. The messages which were not published before the end of the lease
+// are left to the next relay, at worst a message is sent twice, never lost.
+// Every message is published with the context continuing the trace of the change, see writeEvents. Returns the number of published messages
+func (s *PostsService) RelayOutbox(ctx context.Context, shard int, limit int, lease time.Duration, publish func(ctx context.Context, topic string, payload string) error, backoff func(attempts int) time.Duration) (int, error) {
+	client, err := s.getClientPostsShardByIndex(shard)
+	if err != nil {
+		return 0, err
+	}
+	leaseUntil := time.Now().Add(lease)
+	data, err := client.Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		return queries.ClaimUnpublishedOutboxMessages(tx, ctx, limit, leaseUntil)
+	})()
+	if err != nil {
+		return 0, err
+	}
+	messages, ok := data.([]entities.OutboxMessage)
+	if !ok {
+		return 0, fmt.Errorf("unable to convert result into []entities.OutboxMessage")
+	}
+
+	publishErrors := make(map[int]error, len(messages))
+	sent := make([]entities.OutboxMessage, 0, len(messages))
+	for _, message := range messages {
+		if time.Now().After(leaseUntil) {
+			break
+		}
+		publishErrors[message.Id] = publish(tracing.Extract(ctx, decodeTraceContext(message.TraceContext)), message.Topic, message.Payload)
+		sent = append(sent, message)
+	}
+	if len(sent) == 0 {
+		return 0, nil
+	}
+
+	data, err = client.Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		published := 0
+		for _, message := range sent {
+			err := publishErrors[message.Id]
+			if err != nil {
+				err = queries.MarkOutboxMessageFailed(tx, ctx, message.Id, err.Error(), time.Now().Add(backoff(message.Attempts+1)))
+			} else {
+				published++
+				err = queries.MarkOutboxMessagePublished(tx, ctx, message.Id)
+			}
+			if err != nil {
+				return published, err
+			}
+		}
+		return published, nil
+	})()
+	if err != nil {
+		return 0, err
+	}
+
+	published, ok := data.(int)
+	if !ok {
+		return 0, fmt.Errorf("unable to convert result into int")
+	}
+	return published, nil
+}
+
+// PruneOutbox deletes up to limit messages of the shard published before the date. Returns the number of deleted messages
+func (s *PostsService) PruneOutbox(ctx context.Context, shard int, publishedBefore time.Time, limit int) (int, error) {
+	client, err := s.getClientPostsShardByIndex(shard)
+	if err != nil {
+		return 0, err
+	}
+	data, err := client.Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		return queries.DeletePublishedOutboxMessages(tx, ctx, publishedBefore, limit)
+	})()
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, ok := data.(int)
+	if !ok {
+		return 0, fmt.Errorf("unable to convert result into int")
+	}
+	return deleted, nil
+}
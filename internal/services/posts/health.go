@@ -0,0 +1,410 @@
+package posts
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/queries"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/metrics"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/tracing"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/db"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Every shard connection is guarded by the circuit breaker. The breaker opens after the failures in a row, then the requests
+// to the shard fail fast with ErrorShardUnavailable. The open breaker lets a trial request through after the timeout,
+// the periodic health check closes it as soon as the shard answers
+
+const (
+	BREAKER_STATE_CLOSED    = "closed"
+	BREAKER_STATE_OPEN      = "open"
+	BREAKER_STATE_HALF_OPEN = "half_open"
+)
+
+// ShardsHealthConfig sets how the shards are checked and when their circuit breakers open
+type ShardsHealthConfig struct {
+	CheckInterval time.Duration
+	// FailuresThreshold is the number of failures in a row which opens the breaker
+	FailuresThreshold int
+	// OpenTimeout is the time the open breaker rejects the requests before the trial one
+	OpenTimeout time.Duration
+}
+
+// ErrorShardUnavailable is returned without querying the shard while its circuit breaker is open
+type ErrorShardUnavailable struct {
+	Shard      string
+	RetryAfter time.Duration
+}
+
+func (e *ErrorShardUnavailable) Error() string {
+	return fmt.Sprintf("shard '%v' is unavailable, retry after %v", e.Shard, e.RetryAfter)
+}
+
+// Unavailable marks the error as temporary for the cache, so the stale copy could be served, see cache.CacheService.GetOrLoad
+func (e *ErrorShardUnavailable) Unavailable() bool {
+	return true
+}
+
+type circuitBreaker struct {
+	config    *ShardsHealthConfig
+	mutex     sync.Mutex
+	state     string
+	failures  int
+	openUntil time.Time
+	lastError string
+	lastCheck time.Time
+}
+
+func newCircuitBreaker(config *ShardsHealthConfig) *circuitBreaker {
+	return &circuitBreaker{config: config, state: BREAKER_STATE_CLOSED}
+}
+
+// allow returns the time to wait if the request should not be sent to the shard
+func (b *circuitBreaker) allow() (time.Duration, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case BREAKER_STATE_OPEN:
+		if wait := time.Until(b.openUntil); wait > 0 {
+			return wait, false
+		}
+		b.state = BREAKER_STATE_HALF_OPEN
+		return 0, true
+	case BREAKER_STATE_HALF_OPEN:
+		// the trial request is in flight
+		return b.config.CheckInterval, false
+	default:
+		return 0, true
+	}
+}
+
+func (b *circuitBreaker) closed() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.state == BREAKER_STATE_CLOSED
+}
+
+// record counts the result of the request to the shard, err is the failure of the shard itself
+func (b *circuitBreaker) record(name string, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if err == nil {
+		if b.state != BREAKER_STATE_CLOSED {
+			log.Info(fmt.Sprintf("Shard '%v' is available again", name))
+		}
+		b.state = BREAKER_STATE_CLOSED
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	b.lastError = err.Error()
+	if b.state == BREAKER_STATE_HALF_OPEN || b.failures >= b.config.FailuresThreshold {
+		if b.state == BREAKER_STATE_CLOSED {
+			log.Error(fmt.Sprintf("Shard '%v' is unavailable, requests are rejected for %v", name, b.config.OpenTimeout), err.Error())
+		}
+		b.state = BREAKER_STATE_OPEN
+		b.openUntil = time.Now().Add(b.config.OpenTimeout)
+	}
+}
+
+// isShardDown tells the connection failures from the errors of the queries themselves. The expired deadline is not the failure:
+// the slow query and the short deadline of the caller tell nothing about the connection, the shard which does not answer at all
+// is found by the health check
+func isShardDown(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	// context.DeadlineExceeded is the net.Error too, the timeouts of the connection itself are wrapped by net.OpError
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr != context.DeadlineExceeded {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "53300", "57P01", "57P02", "57P03":
+			// too many connections, the server is shutting down or starting
+			return true
+		}
+		return pqErr.Code.Class() == "08"
+	}
+	return false
+}
+
+// shardClient is the connection to the shard guarded by the circuit breaker, it is used the same way as db.PostgreSQLService
+type shardClient struct {
+	name    string
+	client  *db.PostgreSQLService
+	breaker *circuitBreaker
+}
+
+func (s *PostsService) newShardClient(name string, client *db.PostgreSQLService) *shardClient {
+	return &shardClient{name: name, client: client, breaker: newCircuitBreaker(&s.health)}
+}
+
+// Tx runs the transaction within the span. The queries get the context derived from the caller one, so the client disconnect
+// or the expired gRPC deadline aborts the running query, see queryContext
+func (c *shardClient) Tx(ctx context.Context, f func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error)) func() (any, error) {
+	return func() (any, error) {
+		ctx, span := c.startSpan(ctx)
+		if err := c.allow(ctx); err != nil {
+			tracing.End(span, err)
+			return nil, err
+		}
+		start := time.Now()
+		data, err := c.client.Tx(func(tx *sql.Tx, txCtx context.Context, _ context.CancelFunc) (any, error) {
+			ctx, cancel := queryContext(ctx, txCtx)
+			defer cancel()
+			return f(tx, ctx, cancel)
+		})()
+		c.done(ctx, start, err)
+		tracing.End(span, err)
+		return data, err
+	}
+}
+
+func (c *shardClient) TxVoid(ctx context.Context, f func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error) func() error {
+	return func() error {
+		ctx, span := c.startSpan(ctx)
+		if err := c.allow(ctx); err != nil {
+			tracing.End(span, err)
+			return err
+		}
+		start := time.Now()
+		err := c.client.TxVoid(func(tx *sql.Tx, txCtx context.Context, _ context.CancelFunc) error {
+			ctx, cancel := queryContext(ctx, txCtx)
+			defer cancel()
+			return f(tx, ctx, cancel)
+		})()
+		c.done(ctx, start, err)
+		tracing.End(span, err)
+		return err
+	}
+}
+
+// allow fails fast if the caller has already given up or the breaker of the shard is open
+func (c *shardClient) allow(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if wait, ok := c.breaker.allow(); !ok {
+		metrics.CountDBError(c.name, metrics.DB_ERROR_REJECTED)
+		return &ErrorShardUnavailable{Shard: c.name, RetryAfter: wait}
+	}
+	return nil
+}
+
+// queryContext derives the context of the queries from the caller one. The transaction context created by db.PostgreSQLService
+// still limits the queries with DATABASE_QUERY_TIMEOUT_IN_SECONDS, so the earlier of the two deadlines applies
+func queryContext(ctx context.Context, txCtx context.Context) (context.Context, context.CancelFunc) {
+	var result context.Context
+	var cancel context.CancelFunc
+	if deadline, ok := txCtx.Deadline(); ok {
+		result, cancel = context.WithDeadline(ctx, deadline)
+	} else {
+		result, cancel = context.WithCancel(ctx)
+	}
+	stop := context.AfterFunc(txCtx, cancel)
+	return result, func() {
+		stop()
+		cancel()
+	}
+}
+
+// startSpan starts the span only within the trace. The transactions of the background jobs are not traced,
+// otherwise the outbox relay alone would make a trace per shard on every tick
+func (c *shardClient) startSpan(ctx context.Context) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return tracing.Start(ctx, "db "+c.name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, attribute.String("db.shard", c.name)),
+	)
+}
+
+// done records the result of the request, only the errors meaning the shard is down are the failures.
+// The missing rows and the rejected state changes are the usual results, not the errors. The request canceled by the caller
+// tells nothing about the shard
+func (c *shardClient) done(ctx context.Context, start time.Time, err error) {
+	metrics.ObserveDBQuery(c.name, start)
+	switch {
+	case err == nil || errors.Is(err, sql.ErrNoRows) || isRejectedStateChange(err):
+		err = nil
+	case ctx.Err() != nil:
+		metrics.CountDBError(c.name, metrics.DB_ERROR_CANCELED)
+		return
+	case isShardDown(err):
+		metrics.CountDBError(c.name, metrics.DB_ERROR_CONNECTION)
+	default:
+		metrics.CountDBError(c.name, metrics.DB_ERROR_QUERY)
+		err = nil
+	}
+	c.breaker.record(c.name, err)
+}
+
+// check queries the shard bypassing the breaker, so the open breaker is closed without waiting for the trial request
+func (c *shardClient) check() error {
+	err := c.client.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+		return queries.Ping(tx, ctx)
+	})()
+	c.recordCheck(err)
+	return err
+}
+
+// recordCheck counts the result of the health check, any error is the failure
+func (c *shardClient) recordCheck(err error) {
+	c.breaker.record(c.name, err)
+	c.breaker.mutex.Lock()
+	defer c.breaker.mutex.Unlock()
+	c.breaker.lastCheck = time.Now()
+}
+
+func (c *shardClient) Shutdown() error {
+	return c.client.Shutdown()
+}
+
+func (s *PostsService) startShardsHealthChecks() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.health.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.quit:
+				return
+			case <-ticker.C:
+				t := s.topology.Load()
+				checkShards(append([]*shardClient{s.clientTagsShard}, t.clients...))
+			}
+		}
+	}()
+}
+
+// checkShards checks the primaries in parallel, the replicas are checked together with their lag, see checkReplicas
+func checkShards(clients []*shardClient) {
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		wg.Add(1)
+		go func(client *shardClient) {
+			defer wg.Done()
+			client.check()
+		}(client)
+	}
+	wg.Wait()
+}
+
+// PingShards checks the tags shard and every posts shard of the current topology in parallel, bypassing the circuit breakers.
+// The result maps the shard names to the errors, the checks not finished before the context is done get its error
+func (s *PostsService) PingShards(ctx context.Context) map[string]error {
+	t := s.topology.Load()
+	clients := append([]*shardClient{s.clientTagsShard}, t.clients...)
+	errs := make([]error, len(clients))
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Add(1)
+		go func(i int, client *shardClient) {
+			defer wg.Done()
+			done := make(chan error, 1)
+			go func() {
+				done <- client.check()
+			}()
+			select {
+			case errs[i] = <-done:
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+			}
+		}(i, client)
+	}
+	wg.Wait()
+
+	result := make(map[string]error, len(clients))
+	for i, client := range clients {
+		result[client.name] = errs[i]
+	}
+	return result
+}
+
+// ShardHealth is the state of the shard connection at the moment
+type ShardHealth struct {
+	Name string
+	// State is the state of the circuit breaker
+	State string
+	// Usable is set if the requests are sent to the shard, the replicas are used only while their lag is within the limit as well
+	Usable        bool
+	Failures      int
+	LastError     string
+	LastCheckDate time.Time
+	Replicas      []ShardHealth
+}
+
+type HealthReport struct {
+	Shards []ShardHealth
+	Tags   ShardHealth
+}
+
+// Degraded is set if any primary is unavailable, the unavailable replicas are replaced by the primaries
+func (r *HealthReport) Degraded() bool {
+	if r.Tags.State != BREAKER_STATE_CLOSED {
+		return true
+	}
+	for _, shard := range r.Shards {
+		if shard.State != BREAKER_STATE_CLOSED {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *shardClient) health() ShardHealth {
+	c.breaker.mutex.Lock()
+	defer c.breaker.mutex.Unlock()
+	return ShardHealth{
+		Name:          c.name,
+		State:         c.breaker.state,
+		Usable:        c.breaker.state == BREAKER_STATE_CLOSED,
+		Failures:      c.breaker.failures,
+		LastError:     c.breaker.lastError,
+		LastCheckDate: c.breaker.lastCheck,
+	}
+}
+
+func (rs *replicaSet) health() []ShardHealth {
+	result := make([]ShardHealth, 0, len(rs.replicas))
+	for _, r := range rs.replicas {
+		h := r.client.health()
+		h.Usable = h.Usable && r.usable.Load()
+		result = append(result, h)
+	}
+	return result
+}
+
+// Health returns the state of the shards of the current topology and their replicas
+func (s *PostsService) Health() HealthReport {
+	t := s.topology.Load()
+	result := HealthReport{
+		Shards: make([]ShardHealth, len(t.clients)),
+		Tags:   s.clientTagsShard.health(),
+	}
+	result.Tags.Replicas = t.tagsReplicas.health()
+	for i, client := range t.clients {
+		result.Shards[i] = client.health()
+		result.Shards[i].Replicas = t.replicas[i].health()
+	}
+	return result
+}
@@ -0,0 +1,43 @@
+package posts
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestIsShardDown(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		down bool
+	}{
+		{name: "bad connection", err: fmt.Errorf("error at loading post, case after QueryRow.Scan: %w", driver.ErrBadConn), down: true},
+		{name: "connection closed", err: io.ErrUnexpectedEOF, down: true},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, down: true},
+		{name: "too many connections", err: &pq.Error{Code: "53300"}, down: true},
+		{name: "server shutting down", err: &pq.Error{Code: "57P01"}, down: true},
+		{name: "server starting", err: &pq.Error{Code: "57P03"}, down: true},
+		{name: "connection exception", err: &pq.Error{Code: "08006"}, down: true},
+
+		{name: "deadline exceeded", err: fmt.Errorf("error at loading post, case after Query: %w", context.DeadlineExceeded)},
+		{name: "canceled", err: context.Canceled},
+		{name: "query canceled", err: &pq.Error{Code: "57014"}},
+		{name: "unique violation", err: &pq.Error{Code: "23505"}},
+		{name: "no rows", err: sql.ErrNoRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if down := isShardDown(tt.err); down != tt.down {
+				t.Fatalf("isShardDown(%v) = %v, expected %v", tt.err, down, tt.down)
+			}
+		})
+	}
+}
@@ -5,35 +5,184 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/queries"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/pagination"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/db"
-	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/shard"
 )
 
+var ErrorUnknownTags = errors.New("unknown tags")
+
+// retiredShardsShutdownDelay gives the requests started before the topology update the time to finish at the removed shards
+const retiredShardsShutdownDelay = time.Minute
+
 type PostsService struct {
-	clientPostsShards []*db.PostgreSQLService
-	clientTagsShard   *db.PostgreSQLService
-	ShardsNum         int
-	shardService      *shard.ShardService
+	clientTagsShard *shardClient
+	topology        atomic.Pointer[postsTopology]
+	// topologyMutex serializes the topology updates, the readers take the current topology without locking
+	topologyMutex sync.Mutex
+	lagGuard      ReplicaLagGuard
+	health        ShardsHealthConfig
+	recentWrites  *recentWrites
+	quit          chan struct{}
+	wg            sync.WaitGroup
+}
+
+// postsTopology is the immutable snapshot of the shards, it is replaced as a whole on the topology update
+type postsTopology struct {
+	config   *Topology
+	clients  []*shardClient
+	replicas []*replicaSet
+	layout   *ShardsLayout
+	// previousLayout is set while the posts are moved to the new layout, see cmd/reshard
+	previousLayout *ShardsLayout
+	tagsReplicas   *replicaSet
+}
+
+// CreatePostsService connects to the posts shards of the topology. If the topology has the previous layout the posts are being moved
+// from it, so a post is looked up at its shard of the previous layout as well (dual-read window)
+func CreatePostsService(topology *Topology, clientTagsShard *db.PostgreSQLService, lagGuard ReplicaLagGuard, health ShardsHealthConfig) (*PostsService, error) {
+	result := &PostsService{
+		lagGuard: lagGuard,
+		health:   health,
+		// the lag is measured once per interval, so the replica could be behind for the interval more
+		recentWrites: &recentWrites{ttl: lagGuard.MaxLag + lagGuard.CheckInterval, until: make(map[string]time.Time)},
+		quit:         make(chan struct{}),
+	}
+	result.clientTagsShard = result.newShardClient("tags", clientTagsShard)
+	err := result.UpdateTopology(topology)
+	if err != nil {
+		return nil, err
+	}
+	result.startReplicasLagChecks()
+	result.startShardsHealthChecks()
+	return result, nil
 }
 
-func CreatePostsService(clientPostsShards []*db.PostgreSQLService, clientTagsShard *db.PostgreSQLService) *PostsService {
-	return &PostsService{
-		clientPostsShards: clientPostsShards,
-		clientTagsShard:   clientTagsShard,
-		ShardsNum:         len(clientPostsShards),
-		shardService:      shard.CreateShardService(len(clientPostsShards)),
+// UpdateTopology switches the service to the new topology. The connections to the shards and replicas with the same DSN are kept,
+// the connections to the removed or changed ones are closed after a delay. The new replicas are used after their lag is checked
+func (s *PostsService) UpdateTopology(topology *Topology) error {
+	s.topologyMutex.Lock()
+	defer s.topologyMutex.Unlock()
+
+	current := s.topology.Load()
+	if current != nil {
+		err := topology.CheckUpdate(current.config)
+		if err != nil {
+			return err
+		}
+	}
+
+	existing := map[string]*shardClient{}
+	existingReplicas := map[string]*replica{}
+	if current != nil {
+		for i, shard := range current.config.Shards {
+			existing[shard.DSN] = current.clients[i]
+		}
+		for _, r := range current.allReplicas() {
+			existingReplicas[r.dsn] = r
+		}
+	}
+
+	next := &postsTopology{
+		config:   topology,
+		clients:  make([]*shardClient, len(topology.Shards)),
+		replicas: make([]*replicaSet, len(topology.Shards)),
+		layout:   CreateShardsLayout(topology.Weights()),
 	}
+	if previousWeights := topology.PreviousWeights(); previousWeights != nil {
+		next.previousLayout = CreateShardsLayout(previousWeights)
+	}
+	createdReplicas := []*replica{}
+	createdClients := []*shardClient{}
+	for i, shard := range topology.Shards {
+		replicas, created, err := s.createReplicaSet(shard.Name, shard.Replicas, existingReplicas)
+		if err != nil {
+			shutdownCreated(createdClients, createdReplicas)
+			return fmt.Errorf("unable to connect to replica of shard '%v': %w", shard.Name, err)
+		}
+		next.replicas[i] = replicas
+		createdReplicas = append(createdReplicas, created...)
+
+		if client, ok := existing[shard.DSN]; ok {
+			next.clients[i] = client
+			delete(existing, shard.DSN)
+			continue
+		}
+		client, err := CreateShardClient(shard)
+		if err != nil {
+			shutdownCreated(createdClients, createdReplicas)
+			return err
+		}
+		next.clients[i] = s.newShardClient(shard.Name, client)
+		createdClients = append(createdClients, next.clients[i])
+	}
+	tagsReplicas, created, err := s.createReplicaSet("tags", topology.TagsReplicas, existingReplicas)
+	if err != nil {
+		shutdownCreated(createdClients, createdReplicas)
+		return fmt.Errorf("unable to connect to replica of tags shard: %w", err)
+	}
+	next.tagsReplicas = tagsReplicas
+	createdReplicas = append(createdReplicas, created...)
+
+	s.checkReplicas(createdReplicas)
+	s.topology.Store(next)
+
+	retired := []*shardClient{}
+	for _, client := range existing {
+		retired = append(retired, client)
+	}
+	for _, r := range existingReplicas {
+		retired = append(retired, r.client)
+	}
+	for _, client := range retired {
+		client := client
+		time.AfterFunc(retiredShardsShutdownDelay, func() {
+			err := client.Shutdown()
+			if err != nil {
+				log.Error("Unable to close connection to the removed posts shard", err.Error())
+			}
+		})
+	}
+	return nil
+}
+
+// shutdownCreated closes the connections opened for the topology which is not applied, the current topology keeps its own
+func shutdownCreated(clients []*shardClient, replicas []*replica) {
+	for _, r := range replicas {
+		clients = append(clients, r.client)
+	}
+	for _, client := range clients {
+		err := client.Shutdown()
+		if err != nil {
+			log.Error("Unable to close connection to the posts shard of the rejected topology", err.Error())
+		}
+	}
+}
+
+// ShardsCount returns the number of the posts shards of the current topology
+func (s *PostsService) ShardsCount() int {
+	return len(s.topology.Load().clients)
 }
 
 func (s *PostsService) Shutdown() error {
+	close(s.quit)
+	s.wg.Wait()
+
 	result := []error{}
-	l := len(s.clientPostsShards)
-	for i := 0; i < l; i++ {
-		err := s.clientPostsShards[i].Shutdown()
+	t := s.topology.Load()
+	clients := append([]*shardClient{s.clientTagsShard}, t.clients...)
+	for _, r := range t.allReplicas() {
+		clients = append(clients, r.client)
+	}
+	for i := 0; i < len(clients); i++ {
+		err := clients[i].Shutdown()
 		if err != nil {
 			result = append(result, err)
 		}
@@ -44,16 +193,81 @@ func (s *PostsService) Shutdown() error {
 	return nil
 }
 
-func (s *PostsService) getClientPostsShard(postUuid string) *db.PostgreSQLService {
-	bucketIndex := s.shardService.GetBucketIndex(postUuid)
-	bucket := s.shardService.GetBucketByIndex(bucketIndex)
-	log.Info(fmt.Sprintf("bucket: %v\tbucketIndex: %v", bucket, bucketIndex))
-	return s.clientPostsShards[bucket]
+// postShardIndex returns the shard where the post lives. During the dual-read window the post which is not copied yet
+// is still at its shard of the previous layout, so it is read and updated there
+func (t *postsTopology) postShardIndex(ctx context.Context, postUuid string) int {
+	bucket := t.layout.PostShard(postUuid)
+	if t.previousLayout == nil {
+		return bucket
+	}
+
+	previousBucket := t.previousLayout.PostShard(postUuid)
+	if previousBucket == bucket {
+		return bucket
+	}
+
+	data, err := t.clients[bucket].Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		return queries.IsPostExist(tx, ctx, postUuid)
+	})()
+	if err != nil {
+		log.Error(fmt.Sprintf("Unable to check if post '%v' exists at shard %v", postUuid, bucket), err.Error())
+		return bucket
+	}
+	if exists, ok := data.(bool); ok && !exists {
+		return previousBucket
+	}
+	return bucket
+}
+
+// writableClient returns the shard client if the shard is not read-only
+func (t *postsTopology) writableClient(shardIndex int) (*shardClient, error) {
+	if t.config.Shards[shardIndex].State == SHARD_STATE_READ_ONLY {
+		return nil, fmt.Errorf("unable to change posts at shard '%v': %w", t.config.Shards[shardIndex].Name, ErrorShardReadOnly)
+	}
+	return t.clients[shardIndex], nil
+}
+
+// getWritableClientPostsShard returns the primary of the post shard, the following reads of the post and the lists of the shard
+// go to the primary as well
+func (s *PostsService) getWritableClientPostsShard(ctx context.Context, postUuid string) (*shardClient, error) {
+	t := s.topology.Load()
+	shardIndex := t.postShardIndex(ctx, postUuid)
+	s.recentWrites.add(postWriteKey(postUuid), shardWriteKey(shardIndex))
+	return t.writableClient(shardIndex)
+}
+
+// getWritableClientPostsShardForNewPost returns the shard of the post in the current layout, new posts never go to the previous one
+func (s *PostsService) getWritableClientPostsShardForNewPost(postUuid string) (*shardClient, error) {
+	t := s.topology.Load()
+	shardIndex := t.layout.PostShard(postUuid)
+	s.recentWrites.add(postWriteKey(postUuid), shardWriteKey(shardIndex))
+	return t.writableClient(shardIndex)
 }
 
-func (s *PostsService) CreatePost(postUuid string, authorUuid string, text string, previewText string, topic string) (int, error) {
+// getClientPostsShardByIndex returns the primary of the shard by its index at the topology
+func (s *PostsService) getClientPostsShardByIndex(shard int) (*shardClient, error) {
+	clients := s.topology.Load().clients
+	if shard >= len(clients) || shard < 0 {
+		return nil, fmt.Errorf("unexpected shard number: %v", shard)
+	}
+	return clients[shard], nil
+}
+
+func (s *PostsService) getReadClientPostsShardByIndex(shard int) (*shardClient, error) {
+	t := s.topology.Load()
+	if shard >= len(t.clients) || shard < 0 {
+		return nil, fmt.Errorf("unexpected shard number: %v", shard)
+	}
+	return s.readClient(t.clients[shard], t.replicas[shard], shardWriteKey(shard)), nil
+}
+
+func (s *PostsService) CreatePost(ctx context.Context, postUuid string, authorUuid string, text string, previewText string, topic string) (int, error) {
 	var postId int = -1
-	data, err := s.getClientPostsShard(postUuid).Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+	client, err := s.getWritableClientPostsShardForNewPost(postUuid)
+	if err != nil {
+		return postId, err
+	}
+	data, err := client.Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
 		params := &queries.CreatePostParams{
 			Uuid:        postUuid,
 			AuthorUuid:  authorUuid,
@@ -62,7 +276,10 @@ func (s *PostsService) CreatePost(postUuid string, authorUuid string, text strin
 			Topic:       topic,
 		}
 		result, err := queries.CreatePost(tx, ctx, params)
-		return result, err
+		if err != nil {
+			return result, err
+		}
+		return result, writeFirstPostRevision(tx, ctx, postUuid, authorUuid, text, previewText, topic)
 	})()
 
 	if err != nil || data == -1 {
@@ -76,32 +293,237 @@ func (s *PostsService) CreatePost(postUuid string, authorUuid string, text strin
 	return postId, nil
 }
 
-func (s *PostsService) UpdatePost(postUuid string, authorUuid *string, text *string, previewText *string, topic *string, state *string) error {
-	return s.getClientPostsShard(postUuid).TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-		params := &queries.UpdatePostParams{
+// CreatePostWithTags validates tags and creates the post with them in a single transaction
+func (s *PostsService) CreatePostWithTags(ctx context.Context, postUuid string, authorUuid string, text string, previewText string, topic string, tagIds []int, queueTopics ...string) (int, error) {
+	var postId int = -1
+	tagIds = uniqueInts(tagIds)
+	err := s.validateTagIds(ctx, tagIds)
+	if err != nil {
+		return postId, err
+	}
+
+	client, err := s.getWritableClientPostsShardForNewPost(postUuid)
+	if err != nil {
+		return postId, err
+	}
+	data, err := client.Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		params := &queries.CreatePostParams{
 			Uuid:        postUuid,
 			AuthorUuid:  authorUuid,
 			Text:        text,
 			PreviewText: previewText,
 			Topic:       topic,
-			State:       state,
 		}
-		err := queries.UpdatePost(tx, ctx, params)
+		result, err := queries.CreatePost(tx, ctx, params)
+		if err != nil {
+			return result, err
+		}
+		err = writeFirstPostRevision(tx, ctx, postUuid, authorUuid, text, previewText, topic)
+		if err != nil {
+			return result, err
+		}
+		for _, tagId := range tagIds {
+			err = queries.AssignTagToPost(tx, ctx, result, tagId)
+			if err != nil {
+				return result, err
+			}
+		}
+		return result, writePostEvents(tx, ctx, postUuid, queueTopics...)
+	})()
+
+	if err != nil || data == -1 {
+		return postId, err
+	}
+
+	postId, ok := data.(int)
+	if !ok {
+		return postId, fmt.Errorf("unable to convert result into int")
+	}
+	return postId, nil
+}
+
+// UpdatePostWithTags updates the post and replaces its tags (if tagIds is not nil) in a single transaction.
+// The state is changed only if the transition is allowed to the editor, the changed content is stored as the new revision
+func (s *PostsService) UpdatePostWithTags(ctx context.Context, editor Editor, postUuid string, authorUuid *string, text *string, previewText *string, topic *string, state *string, tagIds *[]int, queueTopics ...string) error {
+	var uniqueTagIds []int
+	if tagIds != nil {
+		uniqueTagIds = uniqueInts(*tagIds)
+		err := s.validateTagIds(ctx, uniqueTagIds)
+		if err != nil {
+			return err
+		}
+	}
+
+	client, err := s.getWritableClientPostsShard(ctx, postUuid)
+	if err != nil {
 		return err
+	}
+	return client.TxVoid(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+		err := updatePost(tx, ctx, editor, postUuid, authorUuid, text, previewText, topic, state)
+		if err != nil {
+			return err
+		}
+		if tagIds != nil {
+			post, err := queries.GetPost(tx, ctx, postUuid)
+			if err != nil {
+				return err
+			}
+			err = queries.RemoveAllTagsFromPost(tx, ctx, post.Id)
+			if err != nil && err != sql.ErrNoRows {
+				return err
+			}
+			for _, tagId := range uniqueTagIds {
+				err = queries.AssignTagToPost(tx, ctx, post.Id, tagId)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return writePostEvents(tx, ctx, postUuid, queueTopics...)
 	})()
 }
 
-func (s *PostsService) DeletePost(postUuid string) error {
-	return s.getClientPostsShard(postUuid).TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-		err := queries.DeletePost(tx, ctx, postUuid)
+func (s *PostsService) validateTagIds(ctx context.Context, tagIds []int) error {
+	if len(tagIds) == 0 {
+		return nil
+	}
+	data, err := s.clientTagsShard.Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		tags, err := queries.GetAssignableTagsByIds(tx, ctx, tagIds)
+		return tags, err
+	})()
+	if err != nil {
 		return err
+	}
+
+	tags, ok := data.([]entities.Tag)
+	if !ok {
+		return fmt.Errorf("unable to convert data into []entities.Tag")
+	}
+
+	existing := make(map[int]bool, len(tags))
+	for _, tag := range tags {
+		existing[tag.Id] = true
+	}
+	var unknown []int
+	for _, tagId := range tagIds {
+		if !existing[tagId] {
+			unknown = append(unknown, tagId)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: %v", ErrorUnknownTags, unknown)
+	}
+	return nil
+}
+
+func uniqueInts(input []int) []int {
+	seen := make(map[int]bool, len(input))
+	result := make([]int, 0, len(input))
+	for _, v := range input {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func uniqueStrings(input []string) []string {
+	seen := make(map[string]bool, len(input))
+	result := make([]string, 0, len(input))
+	for _, v := range input {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// uniquePosts keeps the most recently updated copy of each post
+func uniquePosts(input []entities.PostWithTagIds) []entities.PostWithTagIds {
+	indexes := make(map[string]int, len(input))
+	result := make([]entities.PostWithTagIds, 0, len(input))
+	for _, post := range input {
+		i, ok := indexes[post.Post.Uuid]
+		if !ok {
+			indexes[post.Post.Uuid] = len(result)
+			result = append(result, post)
+			continue
+		}
+		if post.Post.LastUpdateDate.After(result[i].Post.LastUpdateDate) {
+			result[i] = post
+		}
+	}
+	return result
+}
+
+func (s *PostsService) UpdatePost(ctx context.Context, editor Editor, postUuid string, authorUuid *string, text *string, previewText *string, topic *string, state *string, queueTopics ...string) error {
+	client, err := s.getWritableClientPostsShard(ctx, postUuid)
+	if err != nil {
+		return err
+	}
+	return client.TxVoid(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+		err := updatePost(tx, ctx, editor, postUuid, authorUuid, text, previewText, topic, state)
+		if err != nil {
+			return err
+		}
+		return writePostEvents(tx, ctx, postUuid, queueTopics...)
+	})()
+}
+
+// DeletePost detaches the tags from the post, deletes it, records the transition to DELETED and writes its events in a single transaction
+func (s *PostsService) DeletePost(ctx context.Context, editor Editor, postUuid string, queueTopics ...string) error {
+	client, err := s.getWritableClientPostsShard(ctx, postUuid)
+	if err != nil {
+		return err
+	}
+	return client.TxVoid(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+		post, err := queries.LockPost(tx, ctx, postUuid)
+		if err != nil {
+			return err
+		}
+		err = queries.RemoveAllTagsFromPost(tx, ctx, post.Id)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		err = queries.DeletePost(tx, ctx, postUuid)
+		if err != nil {
+			return err
+		}
+		err = deletePostState(tx, ctx, editor, post)
+		if err != nil {
+			return err
+		}
+		return writeEvents(tx, ctx, postUuid, queueTopics...)
 	})()
 }
 
-func (s *PostsService) GetPost(postUuid string) (entities.Post, error) {
+func (s *PostsService) GetPost(ctx context.Context, postUuid string) (entities.Post, error) {
+	var result entities.Post
+
+	data, err := s.getReadClientPostsShard(ctx, postUuid).Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		post, err := queries.GetPost(tx, ctx, postUuid)
+		return post, err
+	})()
+	if err != nil {
+		return result, err
+	}
+
+	result, ok := data.(entities.Post)
+	if !ok {
+		return result, fmt.Errorf("unable to convert result into entities.Post")
+	}
+
+	return result, nil
+}
+
+// GetPrimaryPost reads the post from the primary of its shard, the authorization of the changes must not rely on a lagging replica
+func (s *PostsService) GetPrimaryPost(ctx context.Context, postUuid string) (entities.Post, error) {
 	var result entities.Post
 
-	data, err := s.getClientPostsShard(postUuid).Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+	t := s.topology.Load()
+	data, err := t.clients[t.postShardIndex(ctx, postUuid)].Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
 		post, err := queries.GetPost(tx, ctx, postUuid)
 		return post, err
 	})()
@@ -117,12 +539,12 @@ func (s *PostsService) GetPost(postUuid string) (entities.Post, error) {
 	return result, nil
 }
 
-func (s *PostsService) GetPostWithTags(postUuid string) (entities.PostWithTags, error) {
+func (s *PostsService) GetPostWithTags(ctx context.Context, postUuid string) (entities.PostWithTags, error) {
 	var result entities.PostWithTags
 	var postWithTagIds entities.PostWithTagIds
 	var tags []entities.Tag
 
-	data, err := s.getClientPostsShard(postUuid).Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+	data, err := s.getReadClientPostsShard(ctx, postUuid).Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
 		post, err := queries.GetPostWithTagIds(tx, ctx, postUuid)
 		return post, err
 	})()
@@ -136,7 +558,7 @@ func (s *PostsService) GetPostWithTags(postUuid string) (entities.PostWithTags,
 		return result, fmt.Errorf("unable to convert data into entities.PostWithTagIds")
 	}
 
-	data, err = s.clientTagsShard.Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+	data, err = s.getReadClientTagsShard().Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
 		post, err := queries.GetTagsByIds(tx, ctx, postWithTagIds.TagIds)
 		return post, err
 	})()
@@ -153,12 +575,21 @@ func (s *PostsService) GetPostWithTags(postUuid string) (entities.PostWithTags,
 	return entities.PostWithTags{Post: postWithTagIds.Post, Tags: tags, TagIds: postWithTagIds.TagIds}, nil
 }
 
-func (s *PostsService) GetPosts(offset int, limit int, shard int) ([]entities.Post, error) {
-	if shard > s.ShardsNum || shard < 0 {
-		return nil, fmt.Errorf("unexpected shard number: %v", shard)
+// GetPosts returns up to limit posts of the shard ordered by creation date. Pass nil 'after' to get the first page
+func (s *PostsService) GetPosts(ctx context.Context, shard int, limit int, after *pagination.Cursor) ([]entities.Post, error) {
+	client, err := s.getReadClientPostsShardByIndex(shard)
+	if err != nil {
+		return nil, err
 	}
-	data, err := s.clientPostsShards[shard].Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
-		posts, err := queries.GetPosts(tx, ctx, limit, offset)
+	params := &queries.GetPostsParams{
+		Limit: limit,
+	}
+	if after != nil {
+		params.AfterCreateDate = after.CreateDate
+		params.AfterId = after.Id
+	}
+	data, err := client.Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		posts, err := queries.GetPosts(tx, ctx, params)
 		return posts, err
 	})()
 	if err != nil {
@@ -172,9 +603,116 @@ func (s *PostsService) GetPosts(offset int, limit int, shard int) ([]entities.Po
 	return posts, nil
 }
 
-func (s *PostsService) CreateComment(postUuid string, authorUuid string, text string, linkedCommentId *int) (int, error) {
+// GetPostsFeed returns up to limit posts in the given state from all shards, ordered by creation date (newest first).
+// Pass nil 'before' to get the first page, otherwise the cursor (create date and uuid) of the last post of the previous page.
+func (s *PostsService) GetPostsFeed(ctx context.Context, state string, limit int, before *pagination.Cursor) ([]entities.PostWithTags, error) {
+	params := &queries.GetPostsFeedParams{
+		State: state,
+		Limit: limit,
+	}
+	if before != nil {
+		params.BeforeCreateDate = before.CreateDate
+		params.BeforeUuid = before.Uuid
+	}
+
+	t := s.topology.Load()
+	shardsResults := make([][]entities.PostWithTagIds, len(t.clients))
+	shardsErrors := make([]error, len(t.clients))
+	var wg sync.WaitGroup
+	for i := 0; i < len(t.clients); i++ {
+		wg.Add(1)
+		go func(shardIndex int) {
+			defer wg.Done()
+			data, err := s.readClient(t.clients[shardIndex], t.replicas[shardIndex], shardWriteKey(shardIndex)).Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+				posts, err := queries.GetPostsFeed(tx, ctx, params)
+				return posts, err
+			})()
+			if err != nil {
+				shardsErrors[shardIndex] = fmt.Errorf("unable to get posts feed from shard %v: %w", shardIndex, err)
+				return
+			}
+			posts, ok := data.([]entities.PostWithTagIds)
+			if !ok {
+				shardsErrors[shardIndex] = fmt.Errorf("unable to convert result into []entities.PostWithTagIds")
+				return
+			}
+			shardsResults[shardIndex] = posts
+		}(i)
+	}
+	wg.Wait()
+
+	if err := errors.Join(shardsErrors...); err != nil {
+		return nil, err
+	}
+
+	var merged []entities.PostWithTagIds
+	for _, posts := range shardsResults {
+		merged = append(merged, posts...)
+	}
+	if t.previousLayout != nil {
+		merged = uniquePosts(merged)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Post.CreateDate.Equal(merged[j].Post.CreateDate) {
+			return merged[i].Post.Uuid > merged[j].Post.Uuid
+		}
+		return merged[i].Post.CreateDate.After(merged[j].Post.CreateDate)
+	})
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+
+	return s.attachTags(ctx, merged)
+}
+
+func (s *PostsService) attachTags(ctx context.Context, posts []entities.PostWithTagIds) ([]entities.PostWithTags, error) {
+	result := make([]entities.PostWithTags, 0, len(posts))
+	if len(posts) == 0 {
+		return result, nil
+	}
+
+	var tagIds []int
+	for _, post := range posts {
+		tagIds = append(tagIds, post.TagIds...)
+	}
+
+	data, err := s.getReadClientTagsShard().Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		tags, err := queries.GetTagsByIds(tx, ctx, tagIds)
+		return tags, err
+	})()
+	if err != nil {
+		return nil, err
+	}
+
+	tags, ok := data.([]entities.Tag)
+	if !ok {
+		return nil, fmt.Errorf("unable to convert data into []entities.Tag")
+	}
+
+	tagsById := make(map[int]entities.Tag, len(tags))
+	for _, tag := range tags {
+		tagsById[tag.Id] = tag
+	}
+
+	for _, post := range posts {
+		postTags := make([]entities.Tag, 0, len(post.TagIds))
+		for _, tagId := range post.TagIds {
+			if tag, ok := tagsById[tagId]; ok {
+				postTags = append(postTags, tag)
+			}
+		}
+		result = append(result, entities.PostWithTags{Post: post.Post, Tags: postTags, TagIds: post.TagIds})
+	}
+	return result, nil
+}
+
+func (s *PostsService) CreateComment(ctx context.Context, postUuid string, authorUuid string, text string, linkedCommentId *int, queueTopics ...string) (int, error) {
 	var commentId int = -1
-	data, err := s.getClientPostsShard(postUuid).Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+	client, err := s.getWritableClientPostsShard(ctx, postUuid)
+	if err != nil {
+		return commentId, err
+	}
+	data, err := client.Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
 		params := &queries.CreateCommentParams{
 			AuthorUuid:      authorUuid,
 			PostUuid:        postUuid,
@@ -183,7 +721,10 @@ func (s *PostsService) CreateComment(postUuid string, authorUuid string, text st
 		}
 
 		result, err := queries.CreateComment(tx, ctx, params)
-		return result, err
+		if err != nil {
+			return result, err
+		}
+		return result, writeCommentEvents(tx, ctx, postUuid, result, queueTopics...)
 	})()
 
 	if err != nil || data == -1 {
@@ -197,30 +738,40 @@ func (s *PostsService) CreateComment(postUuid string, authorUuid string, text st
 	return commentId, nil
 }
 
-func (s *PostsService) UpdateComment(postUuid string, commentId int, text *string, state *string) error {
-	return s.getClientPostsShard(postUuid).TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-		params := &queries.UpdateCommentParams{
-			Id:    commentId,
-			Text:  text,
-			State: state,
-		}
-		err := queries.UpdateComment(tx, ctx, params)
+// UpdateComment updates the comment, the replaced text is kept at comment_edits with the user who changed it
+func (s *PostsService) UpdateComment(ctx context.Context, editorUuid string, postUuid string, commentId int, text *string, state *string, queueTopics ...string) error {
+	client, err := s.getWritableClientPostsShard(ctx, postUuid)
+	if err != nil {
 		return err
+	}
+	return client.TxVoid(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+		err := updateComment(tx, ctx, editorUuid, postUuid, commentId, text, state)
+		if err != nil {
+			return err
+		}
+		return writeCommentEvents(tx, ctx, postUuid, commentId, queueTopics...)
 	})()
 }
 
-func (s *PostsService) DeleteComment(postUuid string, commentId int) error {
-	return s.getClientPostsShard(postUuid).TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-		err := queries.DeleteComment(tx, ctx, commentId)
+func (s *PostsService) DeleteComment(ctx context.Context, postUuid string, commentId int, queueTopics ...string) error {
+	client, err := s.getWritableClientPostsShard(ctx, postUuid)
+	if err != nil {
 		return err
+	}
+	return client.TxVoid(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+		err := queries.DeleteComment(tx, ctx, postUuid, commentId)
+		if err != nil {
+			return err
+		}
+		return writeDeletedCommentEvents(tx, ctx, postUuid, commentId, queueTopics...)
 	})()
 }
 
-func (s *PostsService) GetComment(postUuid string, commentId int) (entities.Comment, error) {
+func (s *PostsService) GetComment(ctx context.Context, postUuid string, commentId int) (entities.Comment, error) {
 	var result entities.Comment
 
-	data, err := s.getClientPostsShard(postUuid).Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
-		comment, err := queries.GetComment(tx, ctx, commentId)
+	data, err := s.getReadClientPostsShard(ctx, postUuid).Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		comment, err := queries.GetComment(tx, ctx, postUuid, commentId)
 		return comment, err
 	})()
 	if err != nil {
@@ -235,9 +786,39 @@ func (s *PostsService) GetComment(postUuid string, commentId int) (entities.Comm
 	return result, nil
 }
 
-func (s *PostsService) GetComments(postUuid string, offset int, limit int) ([]entities.Comment, error) {
-	data, err := s.getClientPostsShard(postUuid).Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
-		comments, err := queries.GetComments(tx, ctx, postUuid, limit, offset)
+// GetPrimaryComment reads the comment from the primary of the post shard, see GetPrimaryPost
+func (s *PostsService) GetPrimaryComment(ctx context.Context, postUuid string, commentId int) (entities.Comment, error) {
+	var result entities.Comment
+
+	t := s.topology.Load()
+	data, err := t.clients[t.postShardIndex(ctx, postUuid)].Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		comment, err := queries.GetComment(tx, ctx, postUuid, commentId)
+		return comment, err
+	})()
+	if err != nil {
+		return result, err
+	}
+
+	result, ok := data.(entities.Comment)
+	if !ok {
+		return result, fmt.Errorf("unable to convert result into entities.Comment")
+	}
+
+	return result, nil
+}
+
+// GetComments returns up to limit comments of the post ordered by creation date. Pass nil 'after' to get the first page
+func (s *PostsService) GetComments(ctx context.Context, postUuid string, limit int, after *pagination.Cursor) ([]entities.Comment, error) {
+	params := &queries.GetCommentsParams{
+		PostUuid: postUuid,
+		Limit:    limit,
+	}
+	if after != nil {
+		params.AfterCreateDate = after.CreateDate
+		params.AfterId = after.Id
+	}
+	data, err := s.getReadClientPostsShard(ctx, postUuid).Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		comments, err := queries.GetComments(tx, ctx, params)
 		return comments, err
 	})()
 	if err != nil {
@@ -251,26 +832,73 @@ func (s *PostsService) GetComments(postUuid string, offset int, limit int) ([]en
 	return comments, nil
 }
 
-func (s *PostsService) GetTags(offset int, limit int) ([]entities.Tag, error) {
-	data, err := s.clientTagsShard.Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
-		comments, err := queries.GetTags(tx, ctx, limit, offset)
+// GetCommentsThreads returns up to limit root comments of the post ordered by creation date together with their replies up to the given depth.
+// Deleted comments are included, pass nil 'after' to get the first page
+func (s *PostsService) GetCommentsThreads(ctx context.Context, postUuid string, limit int, depth int, after *pagination.Cursor) ([]entities.Comment, error) {
+	params := &queries.GetCommentsThreadsParams{
+		PostUuid: postUuid,
+		Limit:    limit,
+		Depth:    depth,
+	}
+	if after != nil {
+		params.AfterCreateDate = after.CreateDate
+		params.AfterId = after.Id
+	}
+	data, err := s.getReadClientPostsShard(ctx, postUuid).Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		comments, err := queries.GetCommentsThreads(tx, ctx, params)
 		return comments, err
 	})()
 	if err != nil {
 		return nil, err
 	}
 
-	comments, ok := data.([]entities.Tag)
+	comments, ok := data.([]entities.Comment)
 	if !ok {
-		return nil, fmt.Errorf("unable to convert result into []entities.Tag")
+		return nil, fmt.Errorf("unable to convert result into []entities.Comment")
 	}
 	return comments, nil
 }
 
-func (s *PostsService) GetTag(id int) (entities.Tag, error) {
+// GetTags returns up to limit tags ordered by id. Pass nil 'after' to get the first page
+func (s *PostsService) GetTags(ctx context.Context, limit int, after *pagination.Cursor) ([]entities.Tag, error) {
+	params := &queries.GetTagsParams{
+		Limit: limit,
+	}
+	if after != nil {
+		params.AfterId = after.Id
+	}
+	return s.getTags(ctx, func(tx *sql.Tx, ctx context.Context) ([]entities.Tag, error) {
+		return queries.GetTags(tx, ctx, params)
+	})
+}
+
+// GetTagsWithOffset returns the page of tags ordered by id for the gRPC clients which page the tags by offset, it is slow on deep pages
+func (s *PostsService) GetTagsWithOffset(ctx context.Context, offset int, limit int) ([]entities.Tag, error) {
+	return s.getTags(ctx, func(tx *sql.Tx, ctx context.Context) ([]entities.Tag, error) {
+		return queries.GetTagsWithOffset(tx, ctx, offset, limit)
+	})
+}
+
+func (s *PostsService) getTags(ctx context.Context, query func(tx *sql.Tx, ctx context.Context) ([]entities.Tag, error)) ([]entities.Tag, error) {
+	data, err := s.getReadClientTagsShard().Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		tags, err := query(tx, ctx)
+		return tags, err
+	})()
+	if err != nil {
+		return nil, err
+	}
+
+	tags, ok := data.([]entities.Tag)
+	if !ok {
+		return nil, fmt.Errorf("unable to convert result into []entities.Tag")
+	}
+	return tags, nil
+}
+
+func (s *PostsService) GetTag(ctx context.Context, id int) (entities.Tag, error) {
 	var result entities.Tag
 
-	data, err := s.clientTagsShard.Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+	data, err := s.getReadClientTagsShard().Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
 		comment, err := queries.GetTag(tx, ctx, id)
 		return comment, err
 	})()
@@ -286,9 +914,30 @@ func (s *PostsService) GetTag(id int) (entities.Tag, error) {
 	return result, nil
 }
 
-func (s *PostsService) CreateTag(name string) (int, error) {
+// GetPrimaryTag reads the tag from the primary of the tags shard, the tag created a moment ago could be missing at a lagging replica
+func (s *PostsService) GetPrimaryTag(ctx context.Context, id int) (entities.Tag, error) {
+	var result entities.Tag
+
+	data, err := s.clientTagsShard.Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		tag, err := queries.GetTag(tx, ctx, id)
+		return tag, err
+	})()
+	if err != nil {
+		return result, err
+	}
+
+	result, ok := data.(entities.Tag)
+	if !ok {
+		return result, fmt.Errorf("unable to convert result into entities.Tag")
+	}
+
+	return result, nil
+}
+
+func (s *PostsService) CreateTag(ctx context.Context, name string) (int, error) {
 	var result int = -1
-	data, err := s.clientTagsShard.Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+	s.recentWrites.add(tagsWriteKey)
+	data, err := s.clientTagsShard.Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
 		result, err := queries.CreateTag(tx, ctx, name)
 		return result, err
 	})()
@@ -304,19 +953,126 @@ func (s *PostsService) CreateTag(name string) (int, error) {
 	return result, nil
 }
 
-func (s *PostsService) UpdateTag(id int, name string) error {
-	return s.clientTagsShard.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+func (s *PostsService) UpdateTag(ctx context.Context, id int, name string) error {
+	s.recentWrites.add(tagsWriteKey)
+	return s.clientTagsShard.TxVoid(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 		err := queries.UpdateTag(tx, ctx, id, name)
 		return err
 	})()
 }
 
-func (s *PostsService) DeleteTag(id int) error {
-	return fmt.Errorf("not implemented")
+// DeleteTag detaches the tag from posts at all shards and then deletes the tag itself. Returns uuids of the posts the tag was detached from.
+// The tag is marked as deleting first, so it is not assigned to the posts anymore. A post which was being saved with the tag while
+// it was marked could still get it after the shard was passed, so the posts are detached once more after the tag is deleted.
+// Posts are detached before the tag is deleted, so a failed deletion could be safely repeated
+func (s *PostsService) DeleteTag(ctx context.Context, id int) ([]string, error) {
+	_, err := s.GetPrimaryTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// a read-only shard would stop the detaching in the middle, so the shards are checked beforehand
+	t := s.topology.Load()
+	for i := range t.clients {
+		_, err := t.writableClient(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.clientTagsShard.TxVoid(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+		return queries.MarkTagDeleting(tx, ctx, id)
+	})()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.detachTag(ctx, t, id)
+	if err != nil {
+		return result, err
+	}
+
+	err = s.clientTagsShard.TxVoid(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+		err := queries.DeleteTag(tx, ctx, id)
+		return err
+	})()
+	if err != nil {
+		return result, err
+	}
+
+	late, err := s.detachTag(ctx, t, id)
+	return uniqueStrings(append(result, late...)), err
+}
+
+// detachTag removes the tag from the posts at all shards and returns their uuids
+func (s *PostsService) detachTag(ctx context.Context, t *postsTopology, id int) ([]string, error) {
+	var result []string
+	for i := 0; i < len(t.clients); i++ {
+		data, err := t.clients[i].Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+			postUuids, err := queries.GetPostUuidsByTagId(tx, ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			_, err = queries.RemoveTagFromAllPosts(tx, ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			for _, postUuid := range postUuids {
+				err = writePostEvents(tx, ctx, postUuid, UpdatedPostsTagsTopic)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return postUuids, nil
+		})()
+		if err != nil {
+			return result, fmt.Errorf("unable to detach tag %v from posts at shard %v: %w", id, i, err)
+		}
+		postUuids, ok := data.([]string)
+		if !ok {
+			return result, fmt.Errorf("unable to convert result into []string")
+		}
+		if len(postUuids) > 0 {
+			s.recentWrites.add(shardWriteKey(i))
+		}
+		result = append(result, postUuids...)
+	}
+	// during the dual-read window a post could be at two shards
+	result = uniqueStrings(result)
+	for _, postUuid := range result {
+		s.recentWrites.add(postWriteKey(postUuid))
+	}
+	s.recentWrites.add(tagsWriteKey)
+	return result, nil
+}
+
+// CountPostsWithTag returns the number of posts the tag is assigned to at all shards
+func (s *PostsService) CountPostsWithTag(ctx context.Context, id int) (int, error) {
+	result := 0
+	clients := s.topology.Load().clients
+	for i := 0; i < len(clients); i++ {
+		data, err := clients[i].Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+			count, err := queries.CountPostsByTagId(tx, ctx, id)
+			return count, err
+		})()
+		if err != nil {
+			return result, fmt.Errorf("unable to count posts with tag %v at shard %v: %w", id, i, err)
+		}
+		count, ok := data.(int)
+		if !ok {
+			return result, fmt.Errorf("unable to convert result into int")
+		}
+		result += count
+	}
+	return result, nil
 }
 
-func (s *PostsService) AssignTagToPost(postUuid string, tagId int) error {
-	return s.getClientPostsShard(postUuid).TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+func (s *PostsService) AssignTagToPost(ctx context.Context, postUuid string, tagId int) error {
+	client, err := s.getWritableClientPostsShard(ctx, postUuid)
+	if err != nil {
+		return err
+	}
+	return client.TxVoid(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 		post, err := queries.GetPost(tx, ctx, postUuid)
 		if err != nil {
 			return err
@@ -329,8 +1085,12 @@ func (s *PostsService) AssignTagToPost(postUuid string, tagId int) error {
 	})()
 }
 
-func (s *PostsService) RemoveTagFromPost(postUuid string, tagId int) error {
-	return s.getClientPostsShard(postUuid).TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+func (s *PostsService) RemoveTagFromPost(ctx context.Context, postUuid string, tagId int) error {
+	client, err := s.getWritableClientPostsShard(ctx, postUuid)
+	if err != nil {
+		return err
+	}
+	return client.TxVoid(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 		post, err := queries.GetPost(tx, ctx, postUuid)
 		if err != nil {
 			return err
@@ -339,18 +1099,12 @@ func (s *PostsService) RemoveTagFromPost(postUuid string, tagId int) error {
 	})()
 }
 
-func (s *PostsService) RemoveAllTagsFromPost(postUuid string) error {
-	return s.getClientPostsShard(postUuid).TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-		post, err := queries.GetPost(tx, ctx, postUuid)
-		if err != nil {
-			return err
-		}
-		return queries.RemoveAllTagsFromPost(tx, ctx, post.Id)
-	})()
-}
-
-func (s *PostsService) AssignTagsToPost(postUuid string, tagIds []int) error {
-	return s.getClientPostsShard(postUuid).TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+func (s *PostsService) AssignTagsToPost(ctx context.Context, postUuid string, tagIds []int, queueTopics ...string) error {
+	client, err := s.getWritableClientPostsShard(ctx, postUuid)
+	if err != nil {
+		return err
+	}
+	return client.TxVoid(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 		post, err := queries.GetPost(tx, ctx, postUuid)
 		if err != nil {
 			return err
@@ -361,12 +1115,16 @@ func (s *PostsService) AssignTagsToPost(postUuid string, tagIds []int) error {
 				return err
 			}
 		}
-		return nil
+		return writePostEvents(tx, ctx, postUuid, queueTopics...)
 	})()
 }
 
-func (s *PostsService) RemoveTagsFromPost(postUuid string, tagIds []int) error {
-	return s.getClientPostsShard(postUuid).TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+func (s *PostsService) RemoveTagsFromPost(ctx context.Context, postUuid string, tagIds []int) error {
+	client, err := s.getWritableClientPostsShard(ctx, postUuid)
+	if err != nil {
+		return err
+	}
+	return client.TxVoid(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 		post, err := queries.GetPost(tx, ctx, postUuid)
 		if err != nil {
 			return err
@@ -0,0 +1,215 @@
+package posts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/queries"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+)
+
+// The reads go to the replicas whose replication lag is within ReplicaLagGuard.MaxLag, otherwise to the primary.
+// The posts changed by this instance are read from the primary until the replicas are guaranteed to catch up,
+// so a read following a write sees it
+
+// ReplicaLagGuard sets how far the replicas could be behind the primary to be read from
+type ReplicaLagGuard struct {
+	MaxLag        time.Duration
+	CheckInterval time.Duration
+}
+
+type replica struct {
+	dsn    string
+	client *shardClient
+	// usable is set while the last lag check succeeded and the lag was within the limit
+	usable atomic.Bool
+}
+
+// replicaSet is the replicas of the single shard, the reads are spread over them in turn
+type replicaSet struct {
+	replicas []*replica
+	next     atomic.Uint32
+}
+
+// pick returns the next usable replica, nil if there is none
+func (rs *replicaSet) pick() *shardClient {
+	if rs == nil {
+		return nil
+	}
+	l := len(rs.replicas)
+	start := int(rs.next.Add(1))
+	for i := 0; i < l; i++ {
+		r := rs.replicas[(start+i)%l]
+		if r.usable.Load() && r.client.breaker.closed() {
+			return r.client
+		}
+	}
+	return nil
+}
+
+// createReplicaSet reuses the replicas of the previous topology with the same DSN and removes them from existing,
+// the new replicas are returned separately so they could be checked before the first use
+func (s *PostsService) createReplicaSet(shardName string, dsns []string, existing map[string]*replica) (*replicaSet, []*replica, error) {
+	result := &replicaSet{}
+	created := []*replica{}
+	for _, dsn := range dsns {
+		if r, ok := existing[dsn]; ok {
+			result.replicas = append(result.replicas, r)
+			delete(existing, dsn)
+			continue
+		}
+		client, err := createClient(dsn)
+		if err != nil {
+			shutdownCreated(nil, created)
+			return nil, nil, err
+		}
+		r := &replica{dsn: dsn, client: s.newShardClient(replicaName(shardName, dsn), client)}
+		result.replicas = append(result.replicas, r)
+		created = append(created, r)
+	}
+	return result, created, nil
+}
+
+// replicaName identifies the replica by its host without the credentials of the DSN
+func replicaName(shardName string, dsn string) string {
+	params, err := ParseDSN(dsn)
+	if err != nil {
+		return shardName + " replica"
+	}
+	return fmt.Sprintf("%v replica %v:%v", shardName, params.Host, params.Port)
+}
+
+func (t *postsTopology) allReplicas() []*replica {
+	result := []*replica{}
+	for _, rs := range t.replicas {
+		result = append(result, rs.replicas...)
+	}
+	return append(result, t.tagsReplicas.replicas...)
+}
+
+// recentWrites keeps the keys changed by this instance for the time the replicas could be behind
+type recentWrites struct {
+	ttl   time.Duration
+	mutex sync.Mutex
+	until map[string]time.Time
+}
+
+// recentWritesSweepSize is the number of keys after which the expired ones are removed on add
+const recentWritesSweepSize = 1024
+
+func (w *recentWrites) add(keys ...string) {
+	until := time.Now().Add(w.ttl)
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if len(w.until) >= recentWritesSweepSize {
+		now := time.Now()
+		for key, keyUntil := range w.until {
+			if keyUntil.Before(now) {
+				delete(w.until, key)
+			}
+		}
+	}
+	for _, key := range keys {
+		w.until[key] = until
+	}
+}
+
+func (w *recentWrites) contains(key string) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	until, ok := w.until[key]
+	return ok && time.Now().Before(until)
+}
+
+const tagsWriteKey = "tags"
+
+func postWriteKey(postUuid string) string {
+	return "post_" + postUuid
+}
+
+// shardWriteKey is the key of the lists of the posts shard, so the feed shows the post created by the same instance at once
+func shardWriteKey(shardIndex int) string {
+	return "shard_" + strconv.Itoa(shardIndex)
+}
+
+// readClient returns a usable replica unless the key was changed recently, the primary otherwise
+func (s *PostsService) readClient(primary *shardClient, replicas *replicaSet, key string) *shardClient {
+	if key != "" && s.recentWrites.contains(key) {
+		return primary
+	}
+	if client := replicas.pick(); client != nil {
+		return client
+	}
+	return primary
+}
+
+func (s *PostsService) getReadClientPostsShard(ctx context.Context, postUuid string) *shardClient {
+	t := s.topology.Load()
+	shardIndex := t.postShardIndex(ctx, postUuid)
+	return s.readClient(t.clients[shardIndex], t.replicas[shardIndex], postWriteKey(postUuid))
+}
+
+func (s *PostsService) getReadClientTagsShard() *shardClient {
+	return s.readClient(s.clientTagsShard, s.topology.Load().tagsReplicas, tagsWriteKey)
+}
+
+func (s *PostsService) startReplicasLagChecks() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.lagGuard.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.quit:
+				return
+			case <-ticker.C:
+				s.checkReplicas(s.topology.Load().allReplicas())
+			}
+		}
+	}()
+}
+
+// checkReplicas updates the usable flag of the replicas in parallel, the lag check is the health check of the replica as well
+func (s *PostsService) checkReplicas(replicas []*replica) {
+	var wg sync.WaitGroup
+	for _, r := range replicas {
+		wg.Add(1)
+		go func(r *replica) {
+			defer wg.Done()
+			data, err := r.client.client.Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+				return queries.GetReplicationLag(tx, ctx)
+			})()
+			r.client.recordCheck(err)
+			if err != nil {
+				if r.usable.Swap(false) {
+					log.Error("Unable to check replica lag, replica is not used", err.Error())
+				}
+				return
+			}
+			lag, ok := data.(time.Duration)
+			if !ok {
+				r.usable.Store(false)
+				return
+			}
+			if lag == queries.REPLICATION_LAG_UNKNOWN {
+				if r.usable.Swap(false) {
+					log.Info("Replica does not stream from the primary, replica is not used until it reconnects")
+				}
+				return
+			}
+			usable := lag <= s.lagGuard.MaxLag
+			if r.usable.Swap(usable) && !usable {
+				log.Info(fmt.Sprintf("Replica lag %v exceeds %v, replica is not used until it catches up", lag, s.lagGuard.MaxLag))
+			}
+		}(r)
+	}
+	wg.Wait()
+}
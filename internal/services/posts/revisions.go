@@ -0,0 +1,138 @@
+package posts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/queries"
+)
+
+// Every change of the text, the preview text or the topic is stored at post_revisions of the shard in the same transaction
+// as the post itself. The revisions are numbered from 1, the created post gets the first one. The posts created before
+// the revisions get the content they have before the first change as the first revision
+
+// updatePost locks the post and updates it. The state transition is checked and recorded, the changed content is stored as the new revision
+func updatePost(tx *sql.Tx, ctx context.Context, editor Editor, postUuid string, authorUuid *string, text *string, previewText *string, topic *string, state *string) error {
+	post, err := queries.LockPost(tx, ctx, postUuid)
+	if err != nil {
+		return err
+	}
+	if state != nil {
+		err = changePostState(tx, ctx, editor, post, *state)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = queries.UpdatePost(tx, ctx, &queries.UpdatePostParams{
+		Uuid:        postUuid,
+		AuthorUuid:  authorUuid,
+		Text:        text,
+		PreviewText: previewText,
+		Topic:       topic,
+		State:       state,
+	})
+	if err != nil {
+		return err
+	}
+
+	return writePostRevision(tx, ctx, editor, post, text, previewText, topic)
+}
+
+// writePostRevision stores the content of the post after the change as the new revision, nothing is stored if the content is the same
+func writePostRevision(tx *sql.Tx, ctx context.Context, editor Editor, before entities.Post, text *string, previewText *string, topic *string) error {
+	after := before
+	if text != nil {
+		after.Text = *text
+	}
+	if previewText != nil {
+		after.PreviewText = *previewText
+	}
+	if topic != nil {
+		after.Topic = *topic
+	}
+	if after.Text == before.Text && after.PreviewText == before.PreviewText && after.Topic == before.Topic {
+		return nil
+	}
+
+	last, err := queries.GetLastPostRevision(tx, ctx, before.Uuid)
+	if err != nil {
+		return err
+	}
+	if last == 0 {
+		// the editor of the content written before the revisions is unknown
+		last = 1
+		err = queries.CreatePostRevision(tx, ctx, toRevisionParams(before, last, "", before.LastUpdateDate))
+		if err != nil {
+			return err
+		}
+	}
+	return queries.CreatePostRevision(tx, ctx, toRevisionParams(after, last+1, editor.Uuid, time.Now()))
+}
+
+// writeFirstPostRevision stores the content of the created post
+func writeFirstPostRevision(tx *sql.Tx, ctx context.Context, postUuid string, authorUuid string, text string, previewText string, topic string) error {
+	return queries.CreatePostRevision(tx, ctx, &queries.CreatePostRevisionParams{
+		PostUuid:    postUuid,
+		Revision:    1,
+		Text:        text,
+		PreviewText: previewText,
+		Topic:       topic,
+		EditorUuid:  authorUuid,
+		CreateDate:  time.Now(),
+	})
+}
+
+func toRevisionParams(post entities.Post, revision int, editorUuid string, createDate time.Time) *queries.CreatePostRevisionParams {
+	return &queries.CreatePostRevisionParams{
+		PostUuid:    post.Uuid,
+		Revision:    revision,
+		Text:        post.Text,
+		PreviewText: post.PreviewText,
+		Topic:       post.Topic,
+		EditorUuid:  editorUuid,
+		CreateDate:  createDate,
+	}
+}
+
+// GetPostRevisions returns the revisions newest first without their texts, the page starts before the given revision, 0 means the latest one
+func (s *PostsService) GetPostRevisions(ctx context.Context, postUuid string, limit int, beforeRevision int) ([]entities.PostRevision, error) {
+	var result []entities.PostRevision
+
+	data, err := s.getReadClientPostsShard(ctx, postUuid).Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		return queries.GetPostRevisions(tx, ctx, &queries.GetPostRevisionsParams{
+			PostUuid:       postUuid,
+			BeforeRevision: beforeRevision,
+			Limit:          limit,
+		})
+	})()
+	if err != nil {
+		return result, err
+	}
+
+	result, ok := data.([]entities.PostRevision)
+	if !ok {
+		return result, fmt.Errorf("unable to convert result into []entities.PostRevision")
+	}
+	return result, nil
+}
+
+func (s *PostsService) GetPostRevision(ctx context.Context, postUuid string, revision int) (entities.PostRevision, error) {
+	var result entities.PostRevision
+
+	data, err := s.getReadClientPostsShard(ctx, postUuid).Tx(ctx, func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		return queries.GetPostRevision(tx, ctx, postUuid, revision)
+	})()
+	if err != nil {
+		return result, err
+	}
+
+	result, ok := data.(entities.PostRevision)
+	if !ok {
+		return result, fmt.Errorf("unable to convert result into entities.PostRevision")
+	}
+	return result, nil
+}
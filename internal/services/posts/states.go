@@ -0,0 +1,145 @@
+package posts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/queries"
+	utilsEntities "github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/db/entities"
+)
+
+// The post goes through moderation before it is published: NEW -> ON_MODERATION -> PUBLISHED or REJECTED.
+// The author could withdraw the post from moderation, resubmit the rejected one and archive the post,
+// the archived post is published again through moderation. Publishing, rejecting and blocking are the moderation decisions.
+// DELETED is set by the deletion only. Every change of the state, the deletion included, is recorded at post_state_transitions
+
+const (
+	POST_STATE_REJECTED = "REJECTED"
+	POST_STATE_ARCHIVED = "ARCHIVED"
+)
+
+type postTransition struct {
+	to string
+	// moderated transitions are allowed to the owner and the moderators only
+	moderated bool
+}
+
+var postTransitions = map[string][]postTransition{
+	utilsEntities.POST_STATE_NEW: {
+		{to: utilsEntities.POST_STATE_ON_MODERATION},
+		{to: POST_STATE_ARCHIVED},
+	},
+	utilsEntities.POST_STATE_ON_MODERATION: {
+		{to: utilsEntities.POST_STATE_PUBLISHED, moderated: true},
+		{to: POST_STATE_REJECTED, moderated: true},
+		{to: utilsEntities.POST_STATE_NEW},
+	},
+	POST_STATE_REJECTED: {
+		{to: utilsEntities.POST_STATE_ON_MODERATION},
+		{to: POST_STATE_ARCHIVED},
+	},
+	utilsEntities.POST_STATE_PUBLISHED: {
+		{to: utilsEntities.POST_STATE_BLOCKED, moderated: true},
+		{to: POST_STATE_ARCHIVED},
+	},
+	utilsEntities.POST_STATE_BLOCKED: {
+		{to: utilsEntities.POST_STATE_PUBLISHED, moderated: true},
+		{to: POST_STATE_ARCHIVED, moderated: true},
+	},
+	POST_STATE_ARCHIVED: {
+		{to: utilsEntities.POST_STATE_ON_MODERATION},
+	},
+}
+
+var ErrorPostStateForbidden = errors.New("the change of the post state is allowed to the moderators only")
+
+// ErrorPostStateTransition means that the post could not be moved from its current state to the requested one
+type ErrorPostStateTransition struct {
+	From string
+	To   string
+	// Allowed are the states the post could be moved to by the same user
+	Allowed []string
+}
+
+func (e *ErrorPostStateTransition) Error() string {
+	return fmt.Sprintf("unable to change post state from %v to %v, allowed states: %v", e.From, e.To, e.Allowed)
+}
+
+// Editor is the user who changes the post, the moderated transitions are allowed to the moderators only
+type Editor struct {
+	Uuid      string
+	Moderator bool
+}
+
+// PostStates returns the states the post could be updated to
+func PostStates() []string {
+	return []string{
+		utilsEntities.POST_STATE_NEW,
+		utilsEntities.POST_STATE_ON_MODERATION,
+		utilsEntities.POST_STATE_PUBLISHED,
+		POST_STATE_REJECTED,
+		utilsEntities.POST_STATE_BLOCKED,
+		POST_STATE_ARCHIVED,
+	}
+}
+
+// AllowedPostStates returns the states the post could be moved to from the given one
+func AllowedPostStates(from string, moderator bool) []string {
+	result := []string{}
+	for _, t := range postTransitions[from] {
+		if !t.moderated || moderator {
+			result = append(result, t.to)
+		}
+	}
+	return result
+}
+
+func checkPostTransition(from string, to string, moderator bool) error {
+	for _, t := range postTransitions[from] {
+		if t.to != to {
+			continue
+		}
+		if t.moderated && !moderator {
+			return ErrorPostStateForbidden
+		}
+		return nil
+	}
+	return &ErrorPostStateTransition{From: from, To: to, Allowed: AllowedPostStates(from, moderator)}
+}
+
+// changePostState checks the transition from the current state of the post locked by the transaction and records it.
+// The same state is not the transition
+func changePostState(tx *sql.Tx, ctx context.Context, editor Editor, post entities.Post, state string) error {
+	if post.State == state {
+		return nil
+	}
+	err := checkPostTransition(post.State, state, editor.Moderator)
+	if err != nil {
+		return err
+	}
+	return queries.CreatePostStateTransition(tx, ctx, &queries.CreatePostStateTransitionParams{
+		PostUuid:  post.Uuid,
+		FromState: post.State,
+		ToState:   state,
+		ActorUuid: editor.Uuid,
+	})
+}
+
+// deletePostState records the deletion of the post locked by the transaction, the deletion is not the transition checked by the table
+func deletePostState(tx *sql.Tx, ctx context.Context, editor Editor, post entities.Post) error {
+	return queries.CreatePostStateTransition(tx, ctx, &queries.CreatePostStateTransitionParams{
+		PostUuid:  post.Uuid,
+		FromState: post.State,
+		ToState:   utilsEntities.POST_STATE_DELETED,
+		ActorUuid: editor.Uuid,
+	})
+}
+
+// isRejectedStateChange tells the rejected transitions from the failures of the queries
+func isRejectedStateChange(err error) bool {
+	var transitionErr *ErrorPostStateTransition
+	return errors.Is(err, ErrorPostStateForbidden) || errors.As(err, &transitionErr)
+}
@@ -0,0 +1,154 @@
+package posts
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	utilsEntities "github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/db/entities"
+)
+
+func TestCheckPostTransition(t *testing.T) {
+	tests := []struct {
+		name      string
+		from      string
+		to        string
+		moderator bool
+		// forbidden is the moderated transition asked by the author, conflict is the transition which is not allowed at all
+		forbidden bool
+		conflict  bool
+	}{
+		{name: "author submits new post", from: utilsEntities.POST_STATE_NEW, to: utilsEntities.POST_STATE_ON_MODERATION},
+		{name: "author archives new post", from: utilsEntities.POST_STATE_NEW, to: POST_STATE_ARCHIVED},
+		{name: "author withdraws post from moderation", from: utilsEntities.POST_STATE_ON_MODERATION, to: utilsEntities.POST_STATE_NEW},
+		{name: "author resubmits rejected post", from: POST_STATE_REJECTED, to: utilsEntities.POST_STATE_ON_MODERATION},
+		{name: "author archives rejected post", from: POST_STATE_REJECTED, to: POST_STATE_ARCHIVED},
+		{name: "author archives published post", from: utilsEntities.POST_STATE_PUBLISHED, to: POST_STATE_ARCHIVED},
+		{name: "author sends archived post to moderation", from: POST_STATE_ARCHIVED, to: utilsEntities.POST_STATE_ON_MODERATION},
+
+		{name: "author publishes", from: utilsEntities.POST_STATE_ON_MODERATION, to: utilsEntities.POST_STATE_PUBLISHED, forbidden: true},
+		{name: "author rejects", from: utilsEntities.POST_STATE_ON_MODERATION, to: POST_STATE_REJECTED, forbidden: true},
+		{name: "author blocks", from: utilsEntities.POST_STATE_PUBLISHED, to: utilsEntities.POST_STATE_BLOCKED, forbidden: true},
+		{name: "author unblocks", from: utilsEntities.POST_STATE_BLOCKED, to: utilsEntities.POST_STATE_PUBLISHED, forbidden: true},
+		{name: "author archives blocked post", from: utilsEntities.POST_STATE_BLOCKED, to: POST_STATE_ARCHIVED, forbidden: true},
+
+		{name: "moderator publishes", from: utilsEntities.POST_STATE_ON_MODERATION, to: utilsEntities.POST_STATE_PUBLISHED, moderator: true},
+		{name: "moderator rejects", from: utilsEntities.POST_STATE_ON_MODERATION, to: POST_STATE_REJECTED, moderator: true},
+		{name: "moderator blocks", from: utilsEntities.POST_STATE_PUBLISHED, to: utilsEntities.POST_STATE_BLOCKED, moderator: true},
+		{name: "moderator unblocks", from: utilsEntities.POST_STATE_BLOCKED, to: utilsEntities.POST_STATE_PUBLISHED, moderator: true},
+		{name: "moderator archives blocked post", from: utilsEntities.POST_STATE_BLOCKED, to: POST_STATE_ARCHIVED, moderator: true},
+		{name: "moderator submits new post", from: utilsEntities.POST_STATE_NEW, to: utilsEntities.POST_STATE_ON_MODERATION, moderator: true},
+
+		{name: "new post published without moderation", from: utilsEntities.POST_STATE_NEW, to: utilsEntities.POST_STATE_PUBLISHED, conflict: true},
+		{name: "moderator publishes new post", from: utilsEntities.POST_STATE_NEW, to: utilsEntities.POST_STATE_PUBLISHED, moderator: true, conflict: true},
+		{name: "archived post published", from: POST_STATE_ARCHIVED, to: utilsEntities.POST_STATE_PUBLISHED, moderator: true, conflict: true},
+		{name: "blocked post sent to moderation", from: utilsEntities.POST_STATE_BLOCKED, to: utilsEntities.POST_STATE_ON_MODERATION, conflict: true},
+		{name: "deleted by update", from: utilsEntities.POST_STATE_PUBLISHED, to: utilsEntities.POST_STATE_DELETED, moderator: true, conflict: true},
+		{name: "deleted post restored", from: utilsEntities.POST_STATE_DELETED, to: utilsEntities.POST_STATE_NEW, moderator: true, conflict: true},
+		{name: "unknown state", from: "UNKNOWN", to: utilsEntities.POST_STATE_NEW, conflict: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPostTransition(tt.from, tt.to, tt.moderator)
+			var transitionErr *ErrorPostStateTransition
+			switch {
+			case tt.forbidden:
+				if !errors.Is(err, ErrorPostStateForbidden) {
+					t.Fatalf("expected ErrorPostStateForbidden, got %v", err)
+				}
+			case tt.conflict:
+				if !errors.As(err, &transitionErr) {
+					t.Fatalf("expected ErrorPostStateTransition, got %v", err)
+				}
+				if transitionErr.From != tt.from || transitionErr.To != tt.to {
+					t.Fatalf("expected transition %v -> %v, got %v -> %v", tt.from, tt.to, transitionErr.From, transitionErr.To)
+				}
+				if !reflect.DeepEqual(transitionErr.Allowed, AllowedPostStates(tt.from, tt.moderator)) {
+					t.Fatalf("expected allowed states %v, got %v", AllowedPostStates(tt.from, tt.moderator), transitionErr.Allowed)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("expected transition to be allowed, got %v", err)
+				}
+			}
+			if isRejectedStateChange(err) != (tt.forbidden || tt.conflict) {
+				t.Fatalf("isRejectedStateChange(%v) = %v", err, !(tt.forbidden || tt.conflict))
+			}
+		})
+	}
+}
+
+func TestAllowedPostStates(t *testing.T) {
+	tests := []struct {
+		from      string
+		author    []string
+		moderator []string
+	}{
+		{
+			from:      utilsEntities.POST_STATE_NEW,
+			author:    []string{utilsEntities.POST_STATE_ON_MODERATION, POST_STATE_ARCHIVED},
+			moderator: []string{utilsEntities.POST_STATE_ON_MODERATION, POST_STATE_ARCHIVED},
+		},
+		{
+			from:      utilsEntities.POST_STATE_ON_MODERATION,
+			author:    []string{utilsEntities.POST_STATE_NEW},
+			moderator: []string{utilsEntities.POST_STATE_PUBLISHED, POST_STATE_REJECTED, utilsEntities.POST_STATE_NEW},
+		},
+		{
+			from:      POST_STATE_REJECTED,
+			author:    []string{utilsEntities.POST_STATE_ON_MODERATION, POST_STATE_ARCHIVED},
+			moderator: []string{utilsEntities.POST_STATE_ON_MODERATION, POST_STATE_ARCHIVED},
+		},
+		{
+			from:      utilsEntities.POST_STATE_PUBLISHED,
+			author:    []string{POST_STATE_ARCHIVED},
+			moderator: []string{utilsEntities.POST_STATE_BLOCKED, POST_STATE_ARCHIVED},
+		},
+		{
+			from:      utilsEntities.POST_STATE_BLOCKED,
+			author:    []string{},
+			moderator: []string{utilsEntities.POST_STATE_PUBLISHED, POST_STATE_ARCHIVED},
+		},
+		{
+			from:      POST_STATE_ARCHIVED,
+			author:    []string{utilsEntities.POST_STATE_ON_MODERATION},
+			moderator: []string{utilsEntities.POST_STATE_ON_MODERATION},
+		},
+		{
+			from:      utilsEntities.POST_STATE_DELETED,
+			author:    []string{},
+			moderator: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from, func(t *testing.T) {
+			if got := AllowedPostStates(tt.from, false); !reflect.DeepEqual(got, tt.author) {
+				t.Errorf("author: expected %v, got %v", tt.author, got)
+			}
+			if got := AllowedPostStates(tt.from, true); !reflect.DeepEqual(got, tt.moderator) {
+				t.Errorf("moderator: expected %v, got %v", tt.moderator, got)
+			}
+		})
+	}
+}
+
+// TestPostTransitionsTargetKnownStates keeps the table in line with the states accepted by the update
+func TestPostTransitionsTargetKnownStates(t *testing.T) {
+	known := map[string]bool{}
+	for _, state := range PostStates() {
+		known[state] = true
+	}
+	for from, transitions := range postTransitions {
+		if !known[from] {
+			t.Errorf("transitions from unknown state %v", from)
+		}
+		for _, transition := range transitions {
+			if !known[transition.to] {
+				t.Errorf("transition %v -> %v to unknown state", from, transition.to)
+			}
+			if transition.to == from {
+				t.Errorf("transition %v -> %v to the same state", from, transition.to)
+			}
+		}
+	}
+}
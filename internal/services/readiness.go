@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/cache"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/posts"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
+)
+
+// DependencyCheck returns nil if the dependency is available, it should give up when the context is done
+type DependencyCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type CheckResult struct {
+	Name     string
+	Error    error
+	Duration time.Duration
+}
+
+// ReadinessReport is the result of the checks of all dependencies, the instance is ready only if all of them passed
+type ReadinessReport struct {
+	Ready  bool
+	Checks []CheckResult
+	Date   time.Time
+}
+
+// ReadinessChecker checks the dependencies periodically and on demand, the listeners get every new report
+type ReadinessChecker struct {
+	checks   []DependencyCheck
+	timeout  time.Duration
+	interval time.Duration
+	report   atomic.Pointer[ReadinessReport]
+	// checkMutex keeps the listeners from getting the reports out of order
+	checkMutex sync.Mutex
+	mutex      sync.Mutex
+	listeners  []func(ReadinessReport)
+	quit       chan struct{}
+	wg         sync.WaitGroup
+}
+
+func CreateReadinessChecker(checks ...DependencyCheck) *ReadinessChecker {
+	return &ReadinessChecker{
+		checks:   checks,
+		timeout:  utils.EnvVarDurationDefault("READINESS_CHECK_TIMEOUT_IN_SECONDS", time.Second, 2*time.Second),
+		interval: utils.EnvVarDurationDefault("READINESS_CHECK_INTERVAL_IN_SECONDS", time.Second, 5*time.Second),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start makes the first check synchronously, so the report is available right away
+func (c *ReadinessChecker) Start() {
+	c.Check()
+	c.wg.Add(1)
+	go c.watch()
+}
+
+func (c *ReadinessChecker) Shutdown() error {
+	close(c.quit)
+	c.wg.Wait()
+	return nil
+}
+
+func (c *ReadinessChecker) watch() {
+	defer c.wg.Done()
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.quit:
+			return
+		case <-ticker.C:
+			c.Check()
+		}
+	}
+}
+
+// OnUpdate registers the listener and calls it with the last report
+func (c *ReadinessChecker) OnUpdate(listener func(ReadinessReport)) {
+	c.mutex.Lock()
+	c.listeners = append(c.listeners, listener)
+	c.mutex.Unlock()
+	if report := c.report.Load(); report != nil {
+		listener(*report)
+	}
+}
+
+// Report returns the last report
+func (c *ReadinessChecker) Report() ReadinessReport {
+	if report := c.report.Load(); report != nil {
+		return *report
+	}
+	return ReadinessReport{}
+}
+
+// Check runs all checks in parallel, each of them is given the timeout
+func (c *ReadinessChecker) Check() ReadinessReport {
+	c.checkMutex.Lock()
+	defer c.checkMutex.Unlock()
+
+	results := make([]CheckResult, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check DependencyCheck) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+			defer cancel()
+			start := time.Now()
+			err := check.Check(ctx)
+			results[i] = CheckResult{Name: check.Name, Error: err, Duration: time.Since(start)}
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := ReadinessReport{Ready: true, Checks: results, Date: time.Now()}
+	for _, result := range results {
+		if result.Error != nil {
+			report.Ready = false
+		}
+	}
+
+	previous := c.report.Swap(&report)
+	if previous == nil || previous.Ready != report.Ready {
+		if report.Ready {
+			log.Info("Instance is ready")
+		} else {
+			log.Error("Instance is not ready", failedChecks(report))
+		}
+	}
+
+	c.mutex.Lock()
+	listeners := append([]func(ReadinessReport){}, c.listeners...)
+	c.mutex.Unlock()
+	for _, listener := range listeners {
+		listener(report)
+	}
+	return report
+}
+
+func failedChecks(report ReadinessReport) string {
+	result := ""
+	for _, check := range report.Checks {
+		if check.Error != nil {
+			result += fmt.Sprintf("%v: %v; ", check.Name, check.Error)
+		}
+	}
+	return result
+}
+
+// postsShardsCheck checks the tags shard and all posts shards in parallel, the error lists the unavailable ones
+func postsShardsCheck(postsService *posts.PostsService) DependencyCheck {
+	return DependencyCheck{
+		Name: "posts shards",
+		Check: func(ctx context.Context) error {
+			errs := postsService.PingShards(ctx)
+			names := make([]string, 0, len(errs))
+			for name, err := range errs {
+				if err != nil {
+					names = append(names, fmt.Sprintf("%v (%v)", name, err))
+				}
+			}
+			if len(names) > 0 {
+				sort.Strings(names)
+				return fmt.Errorf("unavailable shards: %v", names)
+			}
+			return nil
+		},
+	}
+}
+
+func cacheCheck(cacheService *cache.CacheService) DependencyCheck {
+	return DependencyCheck{
+		Name: "cache",
+		Check: func(ctx context.Context) error {
+			return withContext(ctx, cacheService.Ping)
+		},
+	}
+}
+
+// tcpCheck checks that the service accepts connections, it is used for the clients which don't expose their connections
+func tcpCheck(name string, address string) DependencyCheck {
+	return DependencyCheck{
+		Name: name,
+		Check: func(ctx context.Context) error {
+			var dialer net.Dialer
+			conn, err := dialer.DialContext(ctx, "tcp", address)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		},
+	}
+}
+
+// withContext returns the context error if the call is not finished before the context is done
+func withContext(ctx context.Context, f func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- f()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
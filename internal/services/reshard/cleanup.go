@@ -0,0 +1,57 @@
+package reshard
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/queries"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+)
+
+// Cleanup deletes the moved posts from the source shards. A post is deleted only if its target copy is equal or newer, so it should
+// be run after the final copy pass when the service does not read the previous layout anymore (POSTS_SHARDS_PREVIOUS_COUNT=0)
+func (r *Resharder) Cleanup() error {
+	for _, source := range r.sourceShards() {
+		deleted, kept := 0, []string{}
+		err := r.forEachPostToMove(source, time.Time{}, 0, func(batch []entities.Post, lastId int) error {
+			for _, post := range batch {
+				state, err := r.comparePost(source, post.Uuid)
+				if err != nil {
+					return err
+				}
+				if state != copyEqual && state != copyNewer {
+					kept = append(kept, post.Uuid)
+					continue
+				}
+				err = r.deletePost(source, post)
+				if err != nil {
+					return err
+				}
+				deleted++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		log.Info(fmt.Sprintf("shard %v: deleted %v posts", source, deleted))
+		if len(kept) > 0 {
+			log.Info(fmt.Sprintf("shard %v: kept %v posts which are not copied: %v", source, len(kept), strings.Join(kept, ", ")))
+		}
+	}
+	return nil
+}
+
+func (r *Resharder) deletePost(source int, post entities.Post) error {
+	err := r.shards[source].TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+		return queries.DeletePostWithComments(tx, ctx, post)
+	})()
+	if err != nil {
+		return fmt.Errorf("unable to delete post '%v' from shard %v: %w", post.Uuid, source, err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package reshard
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/queries"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+)
+
+// Copy makes a pass over every source shard. The first pass copies all posts which are moved, the next ones copy only the posts
+// changed since the previous pass, unless full is set. An interrupted pass goes on from the last copied batch
+func (r *Resharder) Copy(full bool) error {
+	if !r.state.Prepared {
+		return fmt.Errorf("the comments sequences are not prepared, run the prepare step first")
+	}
+	for _, source := range r.sourceShards() {
+		err := r.copyShard(source, full)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resharder) copyShard(source int, full bool) error {
+	shardState := &r.state.Shards[source]
+	if !shardState.InProgress {
+		shardState.InProgress = true
+		shardState.LastPostId = 0
+		shardState.PassStartDate = time.Now()
+		shardState.PassChangedSince = time.Time{}
+		if !full && !shardState.SyncedUntil.IsZero() {
+			shardState.PassChangedSince = shardState.SyncedUntil.Add(-syncMargin)
+		}
+		err := r.state.Save()
+		if err != nil {
+			return err
+		}
+	}
+
+	copied, skipped := 0, 0
+	err := r.forEachPostToMove(source, shardState.PassChangedSince, shardState.LastPostId, func(batch []entities.Post, lastId int) error {
+		for _, post := range batch {
+			ok, err := r.copyPost(source, post.Uuid)
+			if err != nil {
+				return err
+			}
+			if ok {
+				copied++
+			} else {
+				skipped++
+			}
+		}
+		shardState.LastPostId = lastId
+		return r.state.Save()
+	})
+	if err != nil {
+		return err
+	}
+
+	shardState.InProgress = false
+	shardState.SyncedUntil = shardState.PassStartDate
+	log.Info(fmt.Sprintf("shard %v: copied %v posts, kept %v newer posts at the target shards", source, copied, skipped))
+	return r.state.Save()
+}
+
+// copyPost copies the post from the source shard to its shard in the new layout. Returns false if the post is missing
+// at the source or the target copy is newer
+func (r *Resharder) copyPost(source int, postUuid string) (bool, error) {
+	moved, ok, err := loadPost(r.shards[source], postUuid)
+	if err != nil {
+		return false, fmt.Errorf("unable to load post '%v' from shard %v: %w", postUuid, source, err)
+	}
+	if !ok {
+		return false, nil
+	}
+	target := r.to.PostShard(postUuid)
+
+	for {
+		comments := r.withNewCommentIds(moved.comments)
+		commentEdits := r.withNewCommentEditIds(moved.commentEdits)
+		data, err := r.shards[target].Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+			copied, err := queries.CopyPost(tx, ctx, moved.post, comments)
+			if err != nil {
+				return nil, err
+			}
+			err = queries.CopyCommentEdits(tx, ctx, commentEdits)
+			if err != nil {
+				return nil, err
+			}
+			err = queries.CopyPostStateTransitions(tx, ctx, moved.transitions)
+			if err != nil {
+				return nil, err
+			}
+			return copied, queries.CopyPostRevisions(tx, ctx, moved.revisions)
+		})()
+
+		var collision *queries.ErrorCommentIdCollision
+		if errors.As(err, &collision) {
+			err = r.moveCommentId(target, collision)
+			if err != nil {
+				return false, err
+			}
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("unable to copy post '%v' to shard %v: %w", postUuid, target, err)
+		}
+
+		result, ok := data.(bool)
+		if !ok {
+			return false, fmt.Errorf("unable to convert result into bool")
+		}
+		return result, nil
+	}
+}
+
+// moveCommentId gives the comment the new id at the target shard, the source comment keeps the old one.
+// The ids given by the target sequence after the prepare step are unique among all shards
+func (r *Resharder) moveCommentId(target int, collision *queries.ErrorCommentIdCollision) error {
+	data, err := r.shards[target].Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		return queries.NextCommentId(tx, ctx)
+	})()
+	if err != nil {
+		return fmt.Errorf("unable to get new id for comment %v of post '%v': %w", collision.CommentId, collision.PostUuid, err)
+	}
+	newId, ok := data.(int)
+	if !ok {
+		return fmt.Errorf("unable to convert result into int")
+	}
+
+	r.state.CommentIds[commentKey(collision.PostUuid, collision.CommentId)] = newId
+	log.Info(fmt.Sprintf("comment %v of post '%v' gets id %v at shard %v", collision.CommentId, collision.PostUuid, newId, target))
+	return r.state.Save()
+}
+
+// withNewCommentIds returns the comments with the ids given by moveCommentId, the replies are linked to the new ids as well
+func (r *Resharder) withNewCommentIds(comments []entities.Comment) []entities.Comment {
+	result := make([]entities.Comment, len(comments))
+	for i, comment := range comments {
+		if newId, ok := r.state.CommentIds[commentKey(comment.PostUuid, comment.Id)]; ok {
+			comment.Id = newId
+		}
+		if comment.LinkedCommentId != nil {
+			if newId, ok := r.state.CommentIds[commentKey(comment.PostUuid, *comment.LinkedCommentId)]; ok {
+				comment.LinkedCommentId = &newId
+			}
+		}
+		result[i] = comment
+	}
+	return result
+}
+
+// withNewCommentEditIds returns the edits of the comments which got the new ids by moveCommentId
+func (r *Resharder) withNewCommentEditIds(edits []entities.CommentEdit) []entities.CommentEdit {
+	result := make([]entities.CommentEdit, len(edits))
+	for i, edit := range edits {
+		if newId, ok := r.state.CommentIds[commentKey(edit.PostUuid, edit.CommentId)]; ok {
+			edit.CommentId = newId
+		}
+		result[i] = edit
+	}
+	return result
+}
@@ -0,0 +1,204 @@
+package reshard
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/queries"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/posts"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/db"
+)
+
+// Resharder moves the posts with their tags, comments with their edits, state transitions and revisions from the shards of one layout
+// to the shards of another one. The posts stay at the source shards until the cleanup, so the service could read them
+// during the dual-read window (see POSTS_SHARDS_PREVIOUS_COUNT). The steps are:
+//
+//	plan    - counts the posts to move between each pair of shards
+//	prepare - gives the comments sequences of all shards disjoint ranges, so the new comments never collide
+//	copy    - copies the posts, the first pass is full and the next ones copy only the changes
+//	verify  - compares the counts and the checksums of the source and the target copies
+//	cleanup - deletes the moved posts from the source shards after the dual-read window is closed
+type Resharder struct {
+	shards    []*db.PostgreSQLService
+	from      *posts.ShardsLayout
+	to        *posts.ShardsLayout
+	batchSize int
+	state     *State
+}
+
+// commentsIdsRange is the size of the comments ids range of each shard after the prepare step
+const commentsIdsRange int64 = 1 << 40
+
+// syncMargin is subtracted from the synced date of the shard on the next pass, it covers the clock skew between the service
+// instances and the transactions which were not committed at the start of the previous pass
+const syncMargin = time.Minute
+
+// movedPost is the post at the source shard with everything that is copied along with it
+type movedPost struct {
+	post         entities.PostWithTagIds
+	comments     []entities.Comment
+	commentEdits []entities.CommentEdit
+	transitions  []entities.PostStateTransition
+	revisions    []entities.PostRevision
+}
+
+// CreateResharder moves the posts from the layout with state.FromWeights to the layout with state.ToWeights,
+// the shards are in the order of the topology
+func CreateResharder(shards []*db.PostgreSQLService, state *State, batchSize int) (*Resharder, error) {
+	if len(state.FromWeights) != len(shards) || len(state.ToWeights) != len(shards) {
+		return nil, fmt.Errorf("expected weights of %v shards, got %v -> %v", len(shards), state.FromWeights, state.ToWeights)
+	}
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("unexpected batch size: %v", batchSize)
+	}
+	return &Resharder{
+		shards:    shards,
+		from:      posts.CreateShardsLayout(state.FromWeights),
+		to:        posts.CreateShardsLayout(state.ToWeights),
+		batchSize: batchSize,
+		state:     state,
+	}, nil
+}
+
+// Plan logs how many posts are going to be moved between each pair of shards
+func (r *Resharder) Plan() error {
+	for _, source := range r.sourceShards() {
+		counts := make([]int, len(r.shards))
+		err := r.forEachPostToMove(source, time.Time{}, 0, func(batch []entities.Post, lastId int) error {
+			for _, post := range batch {
+				counts[r.to.PostShard(post.Uuid)]++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for target, count := range counts {
+			if target != source && count > 0 {
+				log.Info(fmt.Sprintf("shard %v -> shard %v: %v posts", source, target, count))
+			}
+		}
+	}
+	return nil
+}
+
+// Prepare moves the comments sequence of each shard to its own range above the greatest comment id of all shards. Without it
+// a comment created at the target shard could get the id of a comment which is not copied yet
+func (r *Resharder) Prepare() error {
+	var greatestId int64
+	for i := range r.shards {
+		data, err := r.shards[i].Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+			return queries.GetCommentsIdSequence(tx, ctx)
+		})()
+		if err != nil {
+			return fmt.Errorf("unable to get comments id sequence of shard %v: %w", i, err)
+		}
+		value, ok := data.(int64)
+		if !ok {
+			return fmt.Errorf("unable to convert result into int64")
+		}
+		greatestId = max(greatestId, value)
+	}
+
+	for i := range r.shards {
+		value := greatestId + int64(i)*commentsIdsRange
+		err := r.shards[i].TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+			return queries.SetCommentsIdSequence(tx, ctx, value)
+		})()
+		if err != nil {
+			return fmt.Errorf("unable to set comments id sequence of shard %v: %w", i, err)
+		}
+		log.Info(fmt.Sprintf("shard %v: comments ids start after %v", i, value))
+	}
+
+	r.state.Prepared = true
+	return r.state.Save()
+}
+
+// sourceShards returns the indexes of the shards which store posts in the previous layout
+func (r *Resharder) sourceShards() []int {
+	result := []int{}
+	for i, weight := range r.state.FromWeights {
+		if weight > 0 {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// forEachPostToMove calls handle with the batches of the source shard posts which belong to another shard in the new layout.
+// The posts created at the source shard in the new layout are skipped. lastId is the id the next batch starts after
+func (r *Resharder) forEachPostToMove(source int, changedSince time.Time, afterId int, handle func(batch []entities.Post, lastId int) error) error {
+	for {
+		params := &queries.GetPostsForReshardParams{
+			AfterId:      afterId,
+			Limit:        r.batchSize,
+			ChangedSince: changedSince,
+		}
+		data, err := r.shards[source].Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+			return queries.GetPostsForReshard(tx, ctx, params)
+		})()
+		if err != nil {
+			return fmt.Errorf("unable to get posts of shard %v: %w", source, err)
+		}
+		all, ok := data.([]entities.Post)
+		if !ok {
+			return fmt.Errorf("unable to convert result into []entities.Post")
+		}
+		if len(all) == 0 {
+			return nil
+		}
+
+		batch := make([]entities.Post, 0, len(all))
+		for _, post := range all {
+			if r.from.PostShard(post.Uuid) == source && r.to.PostShard(post.Uuid) != source {
+				batch = append(batch, post)
+			}
+		}
+		afterId = all[len(all)-1].Id
+		err = handle(batch, afterId)
+		if err != nil {
+			return err
+		}
+		if len(all) < r.batchSize {
+			return nil
+		}
+	}
+}
+
+// loadPost reads the post with its tags, comments, state transitions and revisions, ok is false if the post is missing
+func loadPost(client *db.PostgreSQLService, postUuid string) (movedPost, bool, error) {
+	var result movedPost
+	data, err := client.Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		post, comments, err := queries.GetPostForReshard(tx, ctx, postUuid)
+		if err != nil {
+			return nil, err
+		}
+		transitions, err := queries.GetPostStateTransitions(tx, ctx, postUuid)
+		if err != nil {
+			return nil, err
+		}
+		commentEdits, err := queries.GetAllCommentEditsByPostUuid(tx, ctx, postUuid)
+		if err != nil {
+			return nil, err
+		}
+		revisions, err := queries.GetAllPostRevisions(tx, ctx, postUuid)
+		return movedPost{post: post, comments: comments, commentEdits: commentEdits, transitions: transitions, revisions: revisions}, err
+	})()
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, false, nil
+	}
+	if err != nil {
+		return result, false, err
+	}
+	result, ok := data.(movedPost)
+	if !ok {
+		return result, false, fmt.Errorf("unable to convert result into movedPost")
+	}
+	return result, true, nil
+}
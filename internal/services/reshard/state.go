@@ -0,0 +1,93 @@
+package reshard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// State is the progress of the resharding, it is saved after every batch so the tool could be stopped and started again
+type State struct {
+	// FromWeights and ToWeights are the weights of the shards in the previous and the new layouts
+	FromWeights []int
+	ToWeights   []int
+	// Prepared is set when the comments sequences of all shards got disjoint ranges
+	Prepared bool
+	// Shards keeps the progress of each shard
+	Shards []ShardState
+	// CommentIds keeps the new ids of the comments whose ids were taken at the target shards, the key is "<post uuid>/<old id>"
+	CommentIds map[string]int
+
+	path string
+}
+
+type ShardState struct {
+	// InProgress is set while a pass over the shard is not finished, the pass goes on from LastPostId
+	InProgress bool
+	LastPostId int
+	// PassChangedSince is the date the current pass looks for the changes from, zero means the full pass
+	PassChangedSince time.Time
+	PassStartDate    time.Time
+	// SyncedUntil is the date before which all changes of the shard are copied, zero until the first full pass is finished
+	SyncedUntil time.Time
+}
+
+// LoadState reads the state from the file or creates the new one if the file is missing
+func LoadState(path string, fromWeights []int, toWeights []int) (*State, error) {
+	state := &State{
+		FromWeights: fromWeights,
+		ToWeights:   toWeights,
+		Shards:      make([]ShardState, len(fromWeights)),
+		CommentIds:  make(map[string]int),
+		path:        path,
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read reshard state '%v': %w", path, err)
+	}
+
+	err = json.Unmarshal(data, state)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse reshard state '%v': %w", path, err)
+	}
+	if !slices.Equal(state.FromWeights, fromWeights) || !slices.Equal(state.ToWeights, toWeights) {
+		return nil, fmt.Errorf("reshard state '%v' is for weights %v -> %v, not for %v -> %v", path, state.FromWeights, state.ToWeights, fromWeights, toWeights)
+	}
+	if len(state.Shards) != len(fromWeights) {
+		return nil, fmt.Errorf("reshard state '%v' is broken: expected %v shards, got %v", path, len(fromWeights), len(state.Shards))
+	}
+	if state.CommentIds == nil {
+		state.CommentIds = make(map[string]int)
+	}
+	return state, nil
+}
+
+// Save writes the state through the temporary file, so the previous state is kept if the tool is stopped in the middle
+func (s *State) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal reshard state: %w", err)
+	}
+	tmpPath := s.path + ".tmp"
+	err = os.WriteFile(tmpPath, data, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to write reshard state '%v': %w", tmpPath, err)
+	}
+	err = os.Rename(tmpPath, s.path)
+	if err != nil {
+		return fmt.Errorf("unable to write reshard state '%v': %w", s.path, err)
+	}
+	return nil
+}
+
+func commentKey(postUuid string, commentId int) string {
+	return postUuid + "/" + strconv.Itoa(commentId)
+}
@@ -0,0 +1,173 @@
+package reshard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+)
+
+// VerifyReport is the result of comparing the source and the target copies of the moved posts
+type VerifyReport struct {
+	Checked int
+	Missing int
+	// Mismatched are the posts whose target copy differs from the source one and is not newer
+	Mismatched int
+	// Newer are the posts updated at the target shard after they were copied, it is expected during the dual-read window
+	Newer int
+}
+
+func (r *VerifyReport) Failed() bool {
+	return r.Missing > 0 || r.Mismatched > 0
+}
+
+// Verify compares every moved post with its copy at the target shard by the checksum of the post, its tags, comments,
+// state transitions, revisions and comment edits
+func (r *Resharder) Verify() (*VerifyReport, error) {
+	report := &VerifyReport{}
+	for _, source := range r.sourceShards() {
+		err := r.forEachPostToMove(source, time.Time{}, 0, func(batch []entities.Post, lastId int) error {
+			for _, post := range batch {
+				err := r.verifyPost(source, post.Uuid, report)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return report, err
+		}
+	}
+	log.Info(fmt.Sprintf("checked: %v, missing: %v, mismatched: %v, newer at target: %v", report.Checked, report.Missing, report.Mismatched, report.Newer))
+	return report, nil
+}
+
+func (r *Resharder) verifyPost(source int, postUuid string, report *VerifyReport) error {
+	state, err := r.comparePost(source, postUuid)
+	if err != nil {
+		return err
+	}
+	report.Checked++
+	switch state {
+	case copyMissing:
+		report.Missing++
+		log.Info(fmt.Sprintf("post '%v' is missing at shard %v", postUuid, r.to.PostShard(postUuid)))
+	case copyMismatched:
+		report.Mismatched++
+		log.Info(fmt.Sprintf("post '%v' differs at shard %v", postUuid, r.to.PostShard(postUuid)))
+	case copyNewer:
+		report.Newer++
+	}
+	return nil
+}
+
+type copyState int
+
+const (
+	copyMissing copyState = iota
+	copyMismatched
+	copyNewer
+	copyEqual
+)
+
+// comparePost checks the target copy of the post, the post which is missing at the source is treated as equal
+func (r *Resharder) comparePost(source int, postUuid string) (copyState, error) {
+	sourcePost, ok, err := loadPost(r.shards[source], postUuid)
+	if err != nil {
+		return copyMissing, fmt.Errorf("unable to load post '%v' from shard %v: %w", postUuid, source, err)
+	}
+	if !ok {
+		return copyEqual, nil
+	}
+	target := r.to.PostShard(postUuid)
+	targetPost, ok, err := loadPost(r.shards[target], postUuid)
+	if err != nil {
+		return copyMissing, fmt.Errorf("unable to load post '%v' from shard %v: %w", postUuid, target, err)
+	}
+	if !ok {
+		return copyMissing, nil
+	}
+
+	sourcePost.comments = r.withNewCommentIds(sourcePost.comments)
+	sourcePost.commentEdits = r.withNewCommentEditIds(sourcePost.commentEdits)
+	if checksum(sourcePost) == checksum(targetPost) {
+		return copyEqual, nil
+	}
+	if isNewer(targetPost, sourcePost) {
+		return copyNewer, nil
+	}
+	return copyMismatched, nil
+}
+
+// isNewer checks if the post or any of its comments was updated at the target after the source copy
+func isNewer(target movedPost, source movedPost) bool {
+	if target.post.Post.LastUpdateDate.After(source.post.Post.LastUpdateDate) {
+		return true
+	}
+	sourceDates := make(map[int]time.Time, len(source.comments))
+	for _, comment := range source.comments {
+		sourceDates[comment.Id] = comment.LastUpdateDate
+	}
+	for _, comment := range target.comments {
+		sourceDate, ok := sourceDates[comment.Id]
+		if !ok || comment.LastUpdateDate.After(sourceDate) {
+			return true
+		}
+	}
+	return false
+}
+
+// checksum hashes the fields which are copied, the row ids of the posts, the transitions, the revisions and the comment edits
+// differ between the shards so they are not included
+func checksum(moved movedPost) string {
+	hash := sha256.New()
+	p := moved.post.Post
+	fmt.Fprintf(hash, "%v|%v|%q|%q|%q|%v|%v|%v\n", p.Uuid, p.AuthorUuid, p.Text, p.PreviewText, p.Topic, p.State, p.CreateDate.UnixMicro(), p.LastUpdateDate.UnixMicro())
+
+	tagIds := append([]int{}, moved.post.TagIds...)
+	sort.Ints(tagIds)
+	fmt.Fprintf(hash, "%v\n", tagIds)
+
+	comments := append([]entities.Comment{}, moved.comments...)
+	sort.Slice(comments, func(i, j int) bool { return comments[i].Id < comments[j].Id })
+	for _, c := range comments {
+		linkedCommentId := ""
+		if c.LinkedCommentId != nil {
+			linkedCommentId = fmt.Sprint(*c.LinkedCommentId)
+		}
+		editedAt := ""
+		if c.EditedAt != nil {
+			editedAt = fmt.Sprint(c.EditedAt.UnixMicro())
+		}
+		fmt.Fprintf(hash, "%v|%v|%v|%q|%v|%v|%v|%v|%v|%v\n", c.Id, c.AuthorUuid, c.PostUuid, c.Text, linkedCommentId, c.State, c.CreateDate.UnixMicro(), c.LastUpdateDate.UnixMicro(), c.EditCount, editedAt)
+	}
+
+	transitions := append([]entities.PostStateTransition{}, moved.transitions...)
+	sort.SliceStable(transitions, func(i, j int) bool { return transitions[i].CreateDate.Before(transitions[j].CreateDate) })
+	for _, t := range transitions {
+		fmt.Fprintf(hash, "transition|%v|%v|%v|%v\n", t.FromState, t.ToState, t.ActorUuid, t.CreateDate.UnixMicro())
+	}
+
+	revisions := append([]entities.PostRevision{}, moved.revisions...)
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+	for _, r := range revisions {
+		fmt.Fprintf(hash, "revision|%v|%q|%q|%q|%v|%v\n", r.Revision, r.Text, r.PreviewText, r.Topic, r.EditorUuid, r.CreateDate.UnixMicro())
+	}
+
+	edits := append([]entities.CommentEdit{}, moved.commentEdits...)
+	sort.Slice(edits, func(i, j int) bool {
+		if edits[i].CommentId == edits[j].CommentId {
+			return edits[i].Edit < edits[j].Edit
+		}
+		return edits[i].CommentId < edits[j].CommentId
+	})
+	for _, e := range edits {
+		fmt.Fprintf(hash, "edit|%v|%v|%q|%v|%v\n", e.CommentId, e.Edit, e.Text, e.EditorUuid, e.CreateDate.UnixMicro())
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
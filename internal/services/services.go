@@ -4,24 +4,31 @@ import (
 	"errors"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/cache"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/kafka"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/pagination"
 	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/posts"
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/tracing"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/app"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/auth"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/db"
-	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/kafka"
-	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/shard"
 	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
 )
 
 type Services struct {
-	auth          *auth.AuthGRPCService
-	db            *db.PostgreSQLService
-	posts         *posts.PostsService
-	kafkaProducer *kafka.KafkaProducerService
-	cache         *cache.RedisCacheService
+	auth            *auth.AuthGRPCService
+	db              *db.PostgreSQLService
+	posts           *posts.PostsService
+	kafkaProducer   *kafka.KafkaProducerService
+	cache           *cache.CacheService
+	cursors         *pagination.CursorService
+	outboxRelay     *OutboxRelay
+	topologyWatcher *PostsTopologyWatcher
+	readiness       *ReadinessChecker
+	tracing         *tracing.TracingService
 }
 
 var once sync.Once
@@ -39,6 +46,10 @@ func Instance() *Services {
 const TAGS_SHARD_SUFFIX = "_tags_unique_shard"
 
 func createServices() *Services {
+	tracingService, err := tracing.CreateTracingService()
+	if err != nil {
+		log.Fatalf("unable to create tracing service: %s", err)
+	}
 	authcreds, err := app.LoadTLSCredentialsForClient(utils.EnvVar("AUTH_SERVICE_CLIENT_TLS_CERT_PATH"))
 	if err != nil {
 		log.Fatalf("unable to load TLS credentials: %s", err)
@@ -48,17 +59,9 @@ func createServices() *Services {
 		log.Fatalf("unable to create kafka producer: %s", err)
 	}
 
-	clientsPostsShards := []*db.PostgreSQLService{}
-	for i := 1; i <= shard.DEFAULT_BUCKET_FACTOR; i++ {
-		dbConfig := &db.DBParams{
-			Host:         utils.EnvVar("DATABASE_HOST"),
-			Port:         utils.EnvVar("DATABASE_PORT"),
-			Username:     utils.EnvVar("DATABASE_USER"),
-			Password:     utils.EnvVar("DATABASE_PASSWORD"),
-			DatabaseName: utils.EnvVar("DATABASE_NAME_PREFIX") + "_" + strconv.Itoa(i),
-			SslMode:      utils.EnvVar("DATABASE_SSL_MODE"),
-		}
-		clientsPostsShards = append(clientsPostsShards, db.CreatePostgreSQLService(dbConfig))
+	postsTopology, err := LoadPostsTopology()
+	if err != nil {
+		log.Fatalf("unable to load posts shards topology: %s", err)
 	}
 
 	clientTagsShard := &db.PostgreSQLService{}
@@ -72,17 +75,76 @@ func createServices() *Services {
 	}
 	clientTagsShard = db.CreatePostgreSQLService(dbConfig)
 
+	lagGuard := posts.ReplicaLagGuard{
+		MaxLag:        utils.EnvVarDurationDefault("DATABASE_REPLICA_MAX_LAG_IN_SECONDS", time.Second, 5*time.Second),
+		CheckInterval: utils.EnvVarDurationDefault("DATABASE_REPLICA_LAG_CHECK_INTERVAL_IN_SECONDS", time.Second, 5*time.Second),
+	}
+	failuresThreshold, err := strconv.Atoi(utils.EnvVarDefault("DATABASE_CIRCUIT_BREAKER_FAILURES_THRESHOLD", "5"))
+	if err != nil || failuresThreshold <= 0 {
+		failuresThreshold = 5
+	}
+	shardsHealth := posts.ShardsHealthConfig{
+		CheckInterval:     utils.EnvVarDurationDefault("DATABASE_HEALTH_CHECK_INTERVAL_IN_SECONDS", time.Second, 5*time.Second),
+		FailuresThreshold: failuresThreshold,
+		OpenTimeout:       utils.EnvVarDurationDefault("DATABASE_CIRCUIT_BREAKER_OPEN_TIMEOUT_IN_SECONDS", time.Second, 30*time.Second),
+	}
+	postsService, err := posts.CreatePostsService(postsTopology, clientTagsShard, lagGuard, shardsHealth)
+	if err != nil {
+		log.Fatalf("unable to create posts service: %s", err)
+	}
+	topologyWatcher := CreatePostsTopologyWatcher(postsService)
+	topologyWatcher.Start()
+
+	cacheService, err := cache.CreateCacheService()
+	if err != nil {
+		log.Fatalf("unable to create cache service: %s", err)
+	}
+
+	cursors, err := pagination.CreateCursorService(utils.EnvVar("PAGINATION_CURSOR_SECRET"))
+	if err != nil {
+		log.Fatalf("unable to create cursor service: %s", err)
+	}
+
+	outboxRelay := CreateOutboxRelay(postsService, KafkaPublisher(kafkaProducer))
+	outboxRelay.Start()
+
+	// the clients of Kafka and auth service don't expose their connections, so only the reachability of the services is checked
+	readiness := CreateReadinessChecker(
+		postsShardsCheck(postsService),
+		cacheCheck(cacheService),
+		tcpCheck("kafka", utils.EnvVar("KAFKA_HOST")+":"+utils.EnvVar("KAFKA_PORT")),
+		tcpCheck("auth", utils.EnvVar("AUTH_SERVICE_GRPC_HOST")+":"+utils.EnvVar("AUTH_SERVICE_GRPC_PORT")),
+	)
+	readiness.Start()
+
 	return &Services{
-		auth:          auth.CreateAuthGRPCService(utils.EnvVar("AUTH_SERVICE_GRPC_HOST")+":"+utils.EnvVar("AUTH_SERVICE_GRPC_PORT"), &authcreds),
-		kafkaProducer: kafkaProducer,
-		posts:         posts.CreatePostsService(clientsPostsShards, clientTagsShard),
-		cache:         cache.CreateRedisCacheService(),
+		auth:            auth.CreateAuthGRPCService(utils.EnvVar("AUTH_SERVICE_GRPC_HOST")+":"+utils.EnvVar("AUTH_SERVICE_GRPC_PORT"), &authcreds),
+		kafkaProducer:   kafkaProducer,
+		posts:           postsService,
+		cache:           cacheService,
+		cursors:         cursors,
+		outboxRelay:     outboxRelay,
+		topologyWatcher: topologyWatcher,
+		readiness:       readiness,
+		tracing:         tracingService,
 	}
 }
 
 func (s *Services) Shutdown() error {
 	result := []error{}
-	err := s.auth.Shutdown()
+	err := s.readiness.Shutdown()
+	if err != nil {
+		result = append(result, err)
+	}
+	err = s.outboxRelay.Shutdown()
+	if err != nil {
+		result = append(result, err)
+	}
+	err = s.topologyWatcher.Shutdown()
+	if err != nil {
+		result = append(result, err)
+	}
+	err = s.auth.Shutdown()
 	if err != nil {
 		result = append(result, err)
 	}
@@ -102,6 +164,11 @@ func (s *Services) Shutdown() error {
 	if err != nil {
 		result = append(result, err)
 	}
+	// the last one, so the spans of the shutdown are exported
+	err = s.tracing.Shutdown()
+	if err != nil {
+		result = append(result, err)
+	}
 	if len(result) > 0 {
 		return errors.Join(result...)
 	}
@@ -120,6 +187,14 @@ func (s *Services) Posts() *posts.PostsService {
 	return s.posts
 }
 
-func (s *Services) Cache() *cache.RedisCacheService {
+func (s *Services) Cache() *cache.CacheService {
 	return s.cache
 }
+
+func (s *Services) Cursors() *pagination.CursorService {
+	return s.cursors
+}
+
+func (s *Services) Readiness() *ReadinessChecker {
+	return s.readiness
+}
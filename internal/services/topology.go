@@ -0,0 +1,167 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-posts-service/internal/services/posts"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/log"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/db"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/services/shard"
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
+)
+
+// LoadPostsTopology reads the posts shards topology from the file set by POSTS_SHARDS_CONFIG_PATH. Without the file the shards
+// are the databases DATABASE_NAME_PREFIX_1..N at DATABASE_HOST, N is POSTS_SHARDS_COUNT (POSTS_SHARDS_PREVIOUS_COUNT sets the previous layout),
+// and their replicas are at DATABASE_REPLICA_HOSTS
+func LoadPostsTopology() (*posts.Topology, error) {
+	path := utils.EnvVarDefault("POSTS_SHARDS_CONFIG_PATH", "")
+	if path != "" {
+		return posts.ReadTopology(path)
+	}
+
+	shardsCount, err := strconv.Atoi(utils.EnvVarDefault("POSTS_SHARDS_COUNT", strconv.Itoa(shard.DEFAULT_BUCKET_FACTOR)))
+	if err != nil || shardsCount <= 0 {
+		return nil, fmt.Errorf("unexpected POSTS_SHARDS_COUNT: %v", utils.EnvVarDefault("POSTS_SHARDS_COUNT", ""))
+	}
+	previousShardsCount, err := strconv.Atoi(utils.EnvVarDefault("POSTS_SHARDS_PREVIOUS_COUNT", "0"))
+	if err != nil || previousShardsCount < 0 {
+		return nil, fmt.Errorf("unexpected POSTS_SHARDS_PREVIOUS_COUNT: %v", utils.EnvVarDefault("POSTS_SHARDS_PREVIOUS_COUNT", ""))
+	}
+
+	result := &posts.Topology{}
+	for i := 1; i <= max(shardsCount, previousShardsCount); i++ {
+		dbConfig := &db.DBParams{
+			Host:         utils.EnvVar("DATABASE_HOST"),
+			Port:         utils.EnvVar("DATABASE_PORT"),
+			Username:     utils.EnvVar("DATABASE_USER"),
+			Password:     utils.EnvVar("DATABASE_PASSWORD"),
+			DatabaseName: utils.EnvVar("DATABASE_NAME_PREFIX") + "_" + strconv.Itoa(i),
+			SslMode:      utils.EnvVar("DATABASE_SSL_MODE"),
+		}
+		shardConfig := posts.ShardConfig{
+			Name:  "shard_" + strconv.Itoa(i),
+			DSN:   posts.FormatDSN(dbConfig),
+			State: posts.SHARD_STATE_ACTIVE,
+		}
+		if i <= shardsCount {
+			shardConfig.Weight = 1
+		}
+		if i <= previousShardsCount {
+			shardConfig.PreviousWeight = 1
+		}
+		shardConfig.Replicas = replicasFromEnv(dbConfig)
+		result.Shards = append(result.Shards, shardConfig)
+	}
+	result.TagsReplicas = replicasFromEnv(&db.DBParams{
+		Host:         utils.EnvVar("DATABASE_HOST"),
+		Port:         utils.EnvVar("DATABASE_PORT"),
+		Username:     utils.EnvVar("DATABASE_USER"),
+		Password:     utils.EnvVar("DATABASE_PASSWORD"),
+		DatabaseName: utils.EnvVar("DATABASE_NAME_PREFIX") + TAGS_SHARD_SUFFIX,
+		SslMode:      utils.EnvVar("DATABASE_SSL_MODE"),
+	})
+	return result, result.Validate()
+}
+
+// replicasFromEnv returns the DSNs of the replicas of the database at the hosts listed by DATABASE_REPLICA_HOSTS (host:port,host:port),
+// the replicas have the same database name and credentials as the primary
+func replicasFromEnv(primary *db.DBParams) []string {
+	hosts := utils.EnvVarDefault("DATABASE_REPLICA_HOSTS", "")
+	if hosts == "" {
+		return nil
+	}
+	result := []string{}
+	for _, hostPort := range strings.Split(hosts, ",") {
+		host, port, found := strings.Cut(strings.TrimSpace(hostPort), ":")
+		if !found {
+			port = primary.Port
+		}
+		replica := *primary
+		replica.Host = host
+		replica.Port = port
+		result = append(result, posts.FormatDSN(&replica))
+	}
+	return result
+}
+
+// PostsTopologyWatcher reloads the posts shards topology when its file is changed. An invalid topology is logged and skipped
+type PostsTopologyWatcher struct {
+	posts    *posts.PostsService
+	path     string
+	interval time.Duration
+	modTime  time.Time
+	quit     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func CreatePostsTopologyWatcher(postsService *posts.PostsService) *PostsTopologyWatcher {
+	return &PostsTopologyWatcher{
+		posts:    postsService,
+		path:     utils.EnvVarDefault("POSTS_SHARDS_CONFIG_PATH", ""),
+		interval: utils.EnvVarDurationDefault("POSTS_SHARDS_CONFIG_RELOAD_INTERVAL_IN_SECONDS", time.Second, 10*time.Second),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start does nothing if the topology is not read from the file
+func (w *PostsTopologyWatcher) Start() {
+	if w.path == "" {
+		return
+	}
+	info, err := os.Stat(w.path)
+	if err == nil {
+		w.modTime = info.ModTime()
+	}
+	w.wg.Add(1)
+	go w.watch()
+}
+
+func (w *PostsTopologyWatcher) Shutdown() error {
+	close(w.quit)
+	w.wg.Wait()
+	return nil
+}
+
+func (w *PostsTopologyWatcher) watch() {
+	defer w.wg.Done()
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.quit:
+			return
+		case <-ticker.C:
+			w.reloadIfChanged()
+		}
+	}
+}
+
+func (w *PostsTopologyWatcher) reloadIfChanged() {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		log.Error("Unable to check posts shards topology", err.Error())
+		return
+	}
+	if info.ModTime().Equal(w.modTime) {
+		return
+	}
+	// the file is not reread until it is changed again, even if it is invalid
+	w.modTime = info.ModTime()
+
+	topology, err := posts.ReadTopology(w.path)
+	if err != nil {
+		log.Error("Unable to reload posts shards topology", err.Error())
+		return
+	}
+	err = w.posts.UpdateTopology(topology)
+	if err != nil {
+		log.Error("Unable to apply posts shards topology", err.Error())
+		return
+	}
+	log.Info(fmt.Sprintf("Posts shards topology is reloaded, shards: %v", len(topology.Shards)))
+}
@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// HTTPMiddleware starts the server span continuing the trace of the request headers, the handlers take the context
+// with the span from c.Request.Context(). The skipped routes are not traced, e.g. the probes and the metrics scrapes
+func HTTPMiddleware(skippedRoutes ...string) gin.HandlerFunc {
+	skipped := make(map[string]bool, len(skippedRoutes))
+	for _, route := range skippedRoutes {
+		skipped[route] = true
+	}
+	return func(c *gin.Context) {
+		if skipped[c.FullPath()] {
+			c.Next()
+			return
+		}
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		route := c.FullPath()
+		if route == "" {
+			route = "unknown"
+		}
+		ctx, span := Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPMethodKey.String(c.Request.Method), semconv.HTTPRouteKey.String(route)),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		code := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(code))
+		if code >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(code))
+		}
+	}
+}
+
+// StartGRPC starts the server span continuing the trace of the incoming metadata. The gRPC server is created by the utils,
+// so there is no interceptor and the handlers start the spans themselves, e.g.
+//
+//	ctx, span := tracing.StartGRPC(ctx)
+//	defer tracing.EndGRPC(span, &err)
+func StartGRPC(ctx context.Context) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	method, ok := grpc.Method(ctx)
+	if !ok {
+		method = "unknown"
+	}
+	return Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.RPCSystemKey.String("grpc"), semconv.RPCMethodKey.String(method)),
+	)
+}
+
+// EndGRPC is deferred with the named error result of the handler
+func EndGRPC(span trace.Span, err *error) {
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(status.Code(*err))))
+	End(span, *err)
+}
+
+// metadataCarrier lets the propagator read the trace context from the gRPC metadata
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key string, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	result := make([]string, 0, len(c))
+	for key := range c {
+		result = append(result, key)
+	}
+	return result
+}
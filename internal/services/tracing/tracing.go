@@ -0,0 +1,122 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-utils/pkg/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The spans are started by the REST and gRPC handlers and passed down with the request context through the posts service,
+// the cache and the shards transactions. The trace context is stored together with the outbox events and sent in the Kafka
+// message headers, so the consumers continue the trace of the request which made the change
+
+const (
+	EXPORTER_OTLP   = "otlp"
+	EXPORTER_STDOUT = "stdout"
+	EXPORTER_NONE   = "none"
+)
+
+const instrumentationName = "github.com/ArtemVoronov/indefinite-studies-posts-service"
+
+const shutdownTimeout = 5 * time.Second
+
+type TracingService struct {
+	provider *sdktrace.TracerProvider
+}
+
+// CreateTracingService sets up the global tracer provider with the exporter selected by TRACING_EXPORTER, none by default.
+// Without the exporter the spans are not recorded, but the incoming trace context is still passed to Kafka
+func CreateTracingService() (*TracingService, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch name := utils.EnvVarDefault("TRACING_EXPORTER", EXPORTER_NONE); name {
+	case EXPORTER_OTLP:
+		options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(utils.EnvVarDefault("TRACING_OTLP_ENDPOINT", "localhost:4317"))}
+		if utils.EnvVarDefault("TRACING_OTLP_INSECURE", "false") == "true" {
+			options = append(options, otlptracegrpc.WithInsecure())
+		}
+		// the exporter connects lazily, so the unavailable collector doesn't prevent the start
+		exporter, err = otlptracegrpc.New(context.Background(), options...)
+	case EXPORTER_STDOUT:
+		exporter, err = stdouttrace.New()
+	case EXPORTER_NONE:
+		return &TracingService{}, nil
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter '%v', possible values: %v", name, []string{EXPORTER_OTLP, EXPORTER_STDOUT, EXPORTER_NONE})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to create tracing exporter: %w", err)
+	}
+
+	ratio, err := strconv.ParseFloat(utils.EnvVarDefault("TRACING_SAMPLE_RATIO", "1"), 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		ratio = 1
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(utils.EnvVarDefault("TRACING_SERVICE_NAME", "indefinite-studies-posts-service")),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// the sampling decision of the caller is respected, so the trace is either complete or missing
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return &TracingService{provider: provider}, nil
+}
+
+// Shutdown exports the buffered spans
+func (s *TracingService) Shutdown() error {
+	if s.provider == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return s.provider.Shutdown(ctx)
+}
+
+// Start starts the span as the child of the span of the context
+func Start(ctx context.Context, name string, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, options...)
+}
+
+// End records the error of the operation and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject returns the trace context as the map of the headers, it is empty if the context has no span
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier
+}
+
+// Extract returns the context continuing the trace of the headers returned by Inject
+func Extract(ctx context.Context, headers map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(headers))
+}
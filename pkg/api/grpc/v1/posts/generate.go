@@ -0,0 +1,4 @@
+// Package posts is the client and the server interface of the gRPC API of the posts service, generated from posts.proto
+package posts
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative posts.proto